@@ -16,6 +16,9 @@ import (
 
 const (
 	defaultTimeout = 30 * time.Second
+
+	// AdminAPIPath is the path of the Warpgate admin API relative to the server root
+	AdminAPIPath = "/@warpgate/admin/api"
 )
 
 // Config contains the configuration for the client
@@ -24,6 +27,10 @@ type Config struct {
 	Token              string
 	Timeout            time.Duration
 	InsecureSkipVerify bool
+
+	// Transport overrides the HTTP transport, e.g. to record or replay fixtures
+	// in tests. InsecureSkipVerify has no effect when it is set.
+	Transport http.RoundTripper
 }
 
 // Client is a Warpgate API client
@@ -50,18 +57,37 @@ func NewClient(cfg *Config) (*Client, error) {
 		timeout = cfg.Timeout
 	}
 
+	transport := cfg.Transport
+	if transport == nil {
+		transport = &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify},
+		}
+	}
+
 	return &Client{
 		baseURL: baseURL,
 		token:   cfg.Token,
 		httpClient: &http.Client{
-			Timeout: timeout,
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify},
-			},
+			Timeout:   timeout,
+			Transport: transport,
 		},
 	}, nil
 }
 
+// AdminAPIURL returns the admin API URL for the given Warpgate host, appending
+// AdminAPIPath unless the host already contains it.
+func AdminAPIURL(host string) string {
+	if strings.Contains(host, AdminAPIPath) {
+		return host
+	}
+
+	if strings.HasSuffix(host, "/") {
+		return host + strings.TrimPrefix(AdminAPIPath, "/")
+	}
+
+	return host + AdminAPIPath
+}
+
 // doRequest performs an HTTP request to the Warpgate API with the given method,
 // path, and body. It handles URL resolution, request body serialization, and
 // authentication via token.
@@ -0,0 +1,277 @@
+// Package client provides the API client for interacting with the Warpgate API
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// redacted replaces secret values in recorded fixtures.
+const redacted = "REDACTED"
+
+// scrubbedFields are the JSON object keys whose string values are replaced when
+// recording fixtures, wherever they appear in a request or response body.
+// Certificates are public and kept, as tests need them.
+var scrubbedFields = map[string]bool{
+	"password":    true,
+	"token":       true,
+	"secret":      true,
+	"private_key": true,
+}
+
+// Fixture is a sequence of recorded Warpgate API interactions, stored as JSON
+// golden files.
+type Fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded request and the response it received.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the part of a request that is recorded. Headers are not
+// recorded, so that the API token never ends up in a fixture.
+type RecordedRequest struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// RecordedResponse is a recorded response. JSON bodies are stored as-is for
+// readability, anything else (such as plain text errors) as Text.
+type RecordedResponse struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	Text   string          `json:"text,omitempty"`
+}
+
+// LoadFixture reads a fixture from a JSON file.
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+
+	return &fixture, nil
+}
+
+// Save writes the fixture to a JSON file, creating its directory if needed.
+func (f *Fixture) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fixture: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+
+	return nil
+}
+
+// RecordingTransport is an http.RoundTripper that passes requests on to another
+// transport and records every interaction with secrets scrubbed.
+type RecordingTransport struct {
+	next http.RoundTripper
+
+	mu      sync.Mutex
+	fixture Fixture
+}
+
+// NewRecordingTransport returns a RecordingTransport sending requests through
+// next, or http.DefaultTransport if next is nil.
+func NewRecordingTransport(next http.RoundTripper) *RecordingTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &RecordingTransport{next: next}
+}
+
+// RoundTrip performs the request and records it along with the response.
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			Path:   req.URL.RequestURI(),
+			Body:   scrubJSON(reqBody),
+		},
+		Response: RecordedResponse{
+			Status: resp.StatusCode,
+		},
+	}
+
+	if json.Valid(respBody) {
+		interaction.Response.Body = scrubJSON(respBody)
+	} else {
+		interaction.Response.Text = string(respBody)
+	}
+
+	t.mu.Lock()
+	t.fixture.Interactions = append(t.fixture.Interactions, interaction)
+	t.mu.Unlock()
+
+	return resp, nil
+}
+
+// Fixture returns the interactions recorded so far.
+func (t *RecordingTransport) Fixture() *Fixture {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return &Fixture{Interactions: append([]Interaction(nil), t.fixture.Interactions...)}
+}
+
+// ReplayTransport is an http.RoundTripper that answers requests from a fixture
+// without any network access. Each request is matched against the first unused
+// interaction with the same method and path, so a fixture can contain several
+// responses for the same endpoint that are returned in recorded order.
+type ReplayTransport struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayTransport returns a ReplayTransport answering from the given fixture.
+func NewReplayTransport(fixture *Fixture) *ReplayTransport {
+	return &ReplayTransport{
+		interactions: fixture.Interactions,
+		used:         make([]bool, len(fixture.Interactions)),
+	}
+}
+
+// RoundTrip returns the recorded response for the request, or an error if the
+// fixture has no unused interaction matching it.
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	path := req.URL.RequestURI()
+	for i, interaction := range t.interactions {
+		if t.used[i] || interaction.Request.Method != req.Method || interaction.Request.Path != path {
+			continue
+		}
+		t.used[i] = true
+
+		body := []byte(interaction.Response.Text)
+		contentType := "text/plain; charset=utf-8"
+		if len(interaction.Response.Body) > 0 {
+			body = interaction.Response.Body
+			contentType = "application/json; charset=utf-8"
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+			StatusCode:    interaction.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": []string{contentType}},
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no recorded interaction for %s %s", req.Method, path)
+}
+
+// Unused returns the number of interactions that have not been replayed yet.
+func (t *ReplayTransport) Unused() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	unused := 0
+	for _, used := range t.used {
+		if !used {
+			unused++
+		}
+	}
+	return unused
+}
+
+// scrubJSON replaces the values of secret fields in a JSON document. Empty and
+// non-JSON input is returned as nil, since only JSON bodies are recorded.
+func scrubJSON(data []byte) json.RawMessage {
+	if len(bytes.TrimSpace(data)) == 0 || !json.Valid(data) {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil
+	}
+
+	scrubbed, err := json.Marshal(scrubValue(value))
+	if err != nil {
+		return nil
+	}
+
+	return scrubbed
+}
+
+// scrubValue recursively replaces non-empty string values of scrubbed fields.
+func scrubValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if s, ok := field.(string); ok && s != "" && scrubbedFields[key] {
+				v[key] = redacted
+				continue
+			}
+			v[key] = scrubValue(field)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = scrubValue(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
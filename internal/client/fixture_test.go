@@ -0,0 +1,197 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newFixtureClient returns a client answering from the named fixture in testdata.
+// With WARPGATE_RECORD_FIXTURES=1, it talks to the Warpgate instance in
+// WARPGATE_HOST instead and overwrites the fixture with what it recorded.
+func newFixtureClient(t *testing.T, name string) *Client {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".json")
+
+	if os.Getenv("WARPGATE_RECORD_FIXTURES") == "1" {
+		recorder := NewRecordingTransport(nil)
+		t.Cleanup(func() {
+			if err := recorder.Fixture().Save(path); err != nil {
+				t.Errorf("failed to save fixture: %v", err)
+			}
+		})
+
+		c, err := NewClient(&Config{
+			Host:      AdminAPIURL(os.Getenv("WARPGATE_HOST")),
+			Token:     os.Getenv("WARPGATE_TOKEN"),
+			Transport: recorder,
+		})
+		if err != nil {
+			t.Fatalf("NewClient returned error: %v", err)
+		}
+		return c
+	}
+
+	fixture, err := LoadFixture(path)
+	if err != nil {
+		t.Fatalf("LoadFixture returned error: %v", err)
+	}
+
+	c, err := NewClient(&Config{
+		Host:      AdminAPIURL("https://warpgate.example.com"),
+		Transport: NewReplayTransport(fixture),
+	})
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	return c
+}
+
+func TestRecordingTransportScrubsSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"1","name":"db","options":{"kind":"MySql","password":"hunter2","tls":{"mode":"Disabled","verify":false}}}`))
+	}))
+	defer server.Close()
+
+	recorder := NewRecordingTransport(nil)
+	c, err := NewClient(&Config{
+		Host:      AdminAPIURL(server.URL),
+		Token:     "super-secret-token",
+		Transport: recorder,
+	})
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	target, err := c.CreateTarget(context.Background(), &TargetDataRequest{
+		Name:    "db",
+		Options: TargetMySQLOptions{Kind: "MySql", Password: "hunter2"},
+	})
+	if err != nil {
+		t.Fatalf("CreateTarget returned error: %v", err)
+	}
+
+	// The caller still sees the real response
+	if got := target.Options.(map[string]any)["password"]; got != "hunter2" {
+		t.Fatalf("expected unscrubbed password in response, got %v", got)
+	}
+
+	fixture := recorder.Fixture()
+	if len(fixture.Interactions) != 1 {
+		t.Fatalf("expected 1 interaction, got %d", len(fixture.Interactions))
+	}
+
+	interaction := fixture.Interactions[0]
+	if interaction.Request.Method != http.MethodPost || interaction.Request.Path != AdminAPIPath+"/targets" {
+		t.Fatalf("expected POST %s/targets, got %s %s", AdminAPIPath, interaction.Request.Method, interaction.Request.Path)
+	}
+	if interaction.Response.Status != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", interaction.Response.Status)
+	}
+
+	data, err := json.Marshal(fixture)
+	if err != nil {
+		t.Fatalf("failed to marshal fixture: %v", err)
+	}
+	for _, secret := range []string{"hunter2", "super-secret-token"} {
+		if strings.Contains(string(data), secret) {
+			t.Fatalf("expected %q to be scrubbed from fixture, got %s", secret, data)
+		}
+	}
+}
+
+func TestRecordingTransportKeepsCertificates(t *testing.T) {
+	const certificate = "-----BEGIN CERTIFICATE-----\nMIIB...\n-----END CERTIFICATE-----\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"1","name":"k8s","options":{"kind":"Kubernetes","auth":{"kind":"Certificate","certificate":"` + strings.ReplaceAll(certificate, "\n", `\n`) + `","private_key":"hunter2"}}}`))
+	}))
+	defer server.Close()
+
+	recorder := NewRecordingTransport(nil)
+	c, err := NewClient(&Config{Host: AdminAPIURL(server.URL), Transport: recorder})
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	if _, err := c.CreateTarget(context.Background(), &TargetDataRequest{Name: "k8s"}); err != nil {
+		t.Fatalf("CreateTarget returned error: %v", err)
+	}
+
+	var body struct {
+		Options struct {
+			Auth KubernetesTargetCertificateAuth `json:"auth"`
+		} `json:"options"`
+	}
+	if err := json.Unmarshal(recorder.Fixture().Interactions[0].Response.Body, &body); err != nil {
+		t.Fatalf("failed to parse recorded body: %v", err)
+	}
+
+	if body.Options.Auth.Certificate != certificate {
+		t.Errorf("expected the certificate to be recorded as-is, got %q", body.Options.Auth.Certificate)
+	}
+	if body.Options.Auth.PrivateKey != redacted {
+		t.Errorf("expected the private key to be scrubbed, got %q", body.Options.Auth.PrivateKey)
+	}
+}
+
+func TestReplayTransport(t *testing.T) {
+	fixture := &Fixture{
+		Interactions: []Interaction{
+			{
+				Request:  RecordedRequest{Method: http.MethodGet, Path: AdminAPIPath + "/role/1"},
+				Response: RecordedResponse{Status: http.StatusOK, Body: json.RawMessage(`{"id":"1","name":"first"}`)},
+			},
+			{
+				Request:  RecordedRequest{Method: http.MethodGet, Path: AdminAPIPath + "/role/1"},
+				Response: RecordedResponse{Status: http.StatusNotFound, Text: "not found"},
+			},
+		},
+	}
+
+	replay := NewReplayTransport(fixture)
+	c, err := NewClient(&Config{
+		Host:      AdminAPIURL("https://warpgate.example.com"),
+		Transport: replay,
+	})
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	ctx := context.Background()
+
+	role, err := c.GetRole(ctx, "1")
+	if err != nil {
+		t.Fatalf("GetRole returned error: %v", err)
+	}
+	if role == nil || role.Name != "first" {
+		t.Fatalf("expected role first, got %v", role)
+	}
+
+	// Repeated requests get the next recorded response
+	role, err = c.GetRole(ctx, "1")
+	if err != nil {
+		t.Fatalf("GetRole returned error: %v", err)
+	}
+	if role != nil {
+		t.Fatalf("expected nil role after deletion, got %v", role)
+	}
+
+	if replay.Unused() != 0 {
+		t.Fatalf("expected all interactions to be used, got %d unused", replay.Unused())
+	}
+
+	if _, err := c.GetRole(ctx, "1"); err == nil {
+		t.Fatalf("expected error for unrecorded request, got nil")
+	}
+}
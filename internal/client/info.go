@@ -0,0 +1,98 @@
+// Package client provides types and functions for interacting with Warpgate API
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// PublicAPIPath is the path of the Warpgate user-facing API relative to the server root
+const PublicAPIPath = "/@warpgate/api"
+
+// ServerInfo represents the information Warpgate publishes about itself
+type ServerInfo struct {
+	Version      *string     `json:"version"`
+	ExternalHost *string     `json:"external_host"`
+	Ports        ServerPorts `json:"ports"`
+}
+
+// ServerPorts contains the listener ports of the enabled protocols. Protocols
+// that are disabled have no port.
+type ServerPorts struct {
+	SSH        *int `json:"ssh"`
+	HTTP       *int `json:"http"`
+	MySQL      *int `json:"mysql"`
+	Postgres   *int `json:"postgres"`
+	Kubernetes *int `json:"kubernetes"`
+}
+
+// Version is a Warpgate release version
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseVersion parses a version such as "v0.14.1" or "0.15.0-beta.1". Any
+// pre-release or build suffix is ignored.
+func ParseVersion(s string) (Version, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(trimmed, "-+ "); i >= 0 {
+		trimmed = trimmed[:i]
+	}
+
+	parts := strings.Split(trimmed, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid version: %q", s)
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version: %q", s)
+		}
+		numbers[i] = n
+	}
+
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+// AtLeast reports whether v is the same as or newer than other.
+func (v Version) AtLeast(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major > other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor > other.Minor
+	}
+	return v.Patch >= other.Patch
+}
+
+// String returns the version in "major.minor.patch" form.
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// GetServerInfo retrieves the server information from the Warpgate user-facing
+// API, which lives next to the admin API.
+func (c *Client) GetServerInfo(ctx context.Context) (*ServerInfo, error) {
+	infoURL := *c.baseURL
+	infoURL.Path = strings.TrimSuffix(strings.TrimSuffix(infoURL.Path, "/"), AdminAPIPath) + PublicAPIPath + "/info"
+	infoURL.RawQuery = ""
+
+	resp, err := c.doRequest(ctx, http.MethodGet, infoURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var info ServerInfo
+	if err := handleResponse(resp, &info); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := map[string]Version{
+		"v0.14.1":       {Major: 0, Minor: 14, Patch: 1},
+		"0.15.0":        {Major: 0, Minor: 15, Patch: 0},
+		"0.16.0-beta.1": {Major: 0, Minor: 16, Patch: 0},
+		"1.2":           {Major: 1, Minor: 2, Patch: 0},
+	}
+
+	for input, want := range tests {
+		got, err := ParseVersion(input)
+		if err != nil {
+			t.Fatalf("ParseVersion(%q) returned error: %v", input, err)
+		}
+		if got != want {
+			t.Fatalf("ParseVersion(%q): expected %v, got %v", input, want, got)
+		}
+	}
+
+	for _, input := range []string{"", "dev", "v1", "1.x.0"} {
+		if _, err := ParseVersion(input); err == nil {
+			t.Fatalf("expected error for %q, got nil", input)
+		}
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	v := Version{Major: 0, Minor: 16, Patch: 2}
+
+	if !v.AtLeast(Version{Major: 0, Minor: 16, Patch: 2}) {
+		t.Fatalf("expected %s to be at least itself", v)
+	}
+	if !v.AtLeast(Version{Major: 0, Minor: 15, Patch: 9}) {
+		t.Fatalf("expected %s to be at least 0.15.9", v)
+	}
+	if v.AtLeast(Version{Major: 0, Minor: 17}) {
+		t.Fatalf("expected %s not to be at least 0.17.0", v)
+	}
+	if v.AtLeast(Version{Major: 1}) {
+		t.Fatalf("expected %s not to be at least 1.0.0", v)
+	}
+}
+
+func TestGetServerInfoUsesPublicAPI(t *testing.T) {
+	fixture := &Fixture{
+		Interactions: []Interaction{
+			{
+				Request: RecordedRequest{Method: http.MethodGet, Path: PublicAPIPath + "/info"},
+				Response: RecordedResponse{
+					Status: http.StatusOK,
+					Body:   json.RawMessage(`{"version":"v0.16.2","external_host":"warpgate.example.com","ports":{"ssh":2222,"http":8888,"mysql":null,"postgres":null}}`),
+				},
+			},
+		},
+	}
+
+	c, err := NewClient(&Config{
+		Host:      AdminAPIURL("https://warpgate.example.com"),
+		Transport: NewReplayTransport(fixture),
+	})
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	info, err := c.GetServerInfo(context.Background())
+	if err != nil {
+		t.Fatalf("GetServerInfo returned error: %v", err)
+	}
+
+	if info.Version == nil || *info.Version != "v0.16.2" {
+		t.Fatalf("expected version v0.16.2, got %v", info.Version)
+	}
+	if info.Ports.SSH == nil || *info.Ports.SSH != 2222 {
+		t.Fatalf("expected SSH port 2222, got %v", info.Ports.SSH)
+	}
+	if info.Ports.MySQL != nil {
+		t.Fatalf("expected MySQL to be disabled, got port %d", *info.Ports.MySQL)
+	}
+}
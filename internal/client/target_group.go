@@ -20,6 +20,21 @@ type TargetGroupCreateRequest struct {
 	Color       string `json:"color,omitempty"`
 }
 
+// GetTargetGroups retrieves all target groups from the Warpgate API.
+func (c *Client) GetTargetGroups(ctx context.Context) ([]TargetGroup, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/target-groups", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var targetGroups []TargetGroup
+	if err := handleResponse(resp, &targetGroups); err != nil {
+		return nil, err
+	}
+
+	return targetGroups, nil
+}
+
 // GetTargetGroup retrieves a specific target group by ID from the Warpgate API.
 // Returns nil if the target group is not found.
 func (c *Client) GetTargetGroup(ctx context.Context, id string) (*TargetGroup, error) {
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetTargetsFixture decodes a target list covering every target kind. The
+// fixture is hand-written after the Warpgate API schema, so the test checks the
+// decoder against that schema rather than against payloads of a real server.
+// When recording the fixture, the server needs at least one target of each kind.
+func TestGetTargetsFixture(t *testing.T) {
+	c := newFixtureClient(t, "targets")
+
+	targets, err := c.GetTargets(context.Background(), "")
+	if err != nil {
+		t.Fatalf("GetTargets returned error: %v", err)
+	}
+
+	required := map[string][]string{
+		"WebAdmin":   {},
+		"Ssh":        {"host", "port", "username", "auth"},
+		"Http":       {"url", "tls"},
+		"MySql":      {"host", "port", "username", "tls"},
+		"Postgres":   {"host", "port", "username", "tls"},
+		"Kubernetes": {"cluster_url", "tls", "auth"},
+	}
+
+	seen := make(map[string]bool)
+	for _, target := range targets {
+		if target.ID == "" || target.Name == "" {
+			t.Fatalf("expected target ID and name, got %+v", target)
+		}
+
+		options, ok := target.Options.(map[string]any)
+		if !ok {
+			t.Fatalf("expected options of target %s to decode to an object, got %T", target.Name, target.Options)
+		}
+
+		kind, _ := options["kind"].(string)
+		fields, ok := required[kind]
+		if !ok {
+			t.Fatalf("unexpected kind %q for target %s", kind, target.Name)
+		}
+		for _, field := range fields {
+			if _, ok := options[field]; !ok {
+				t.Fatalf("expected %s options of target %s to contain %q, got %v", kind, target.Name, field, options)
+			}
+		}
+
+		seen[kind] = true
+	}
+
+	for kind := range required {
+		if !seen[kind] {
+			t.Fatalf("expected fixture to contain a %s target", kind)
+		}
+	}
+}
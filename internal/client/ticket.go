@@ -13,7 +13,7 @@ type Ticket struct {
 	Username    string `json:"username,omitempty"`
 	Description string `json:"description,omitempty"`
 	Target      string `json:"target,omitempty"`
-	UsesLeft    string `json:"uses_left,omitempty"`
+	UsesLeft    *int   `json:"uses_left,omitempty"`
 	Expiry      string `json:"expiry,omitempty"`
 	Created     string `json:"created,omitempty"`
 }
@@ -33,6 +33,21 @@ type TicketAndSecret struct {
 	Secret string `json:"secret"`
 }
 
+// GetTickets retrieves all tickets from the Warpgate API.
+func (c *Client) GetTickets(ctx context.Context) ([]Ticket, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/tickets", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var tickets []Ticket
+	if err := handleResponse(resp, &tickets); err != nil {
+		return nil, err
+	}
+
+	return tickets, nil
+}
+
 // CreateTicket creates a new ticket in Warpgate with the provided parameters.
 func (c *Client) CreateTicket(ctx context.Context, req *TicketCreateRequest) (*TicketAndSecret, error) {
 	resp, err := c.doRequest(ctx, http.MethodPost, "/tickets", req)
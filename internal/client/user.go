@@ -17,6 +17,9 @@ const (
 	CredentialKindPassword CredentialKind = "Password"
 	// CredentialKindPublicKey represents public key-based authentication
 	CredentialKindPublicKey CredentialKind = "PublicKey"
+	// CredentialKindCertificate represents client certificate authentication,
+	// which is the only kind Warpgate accepts for Kubernetes
+	CredentialKindCertificate CredentialKind = "Certificate"
 	// CredentialKindSso represents single sign-on authentication
 	CredentialKindTotp CredentialKind = "Totp"
 	// CredentialKindSso represents single sign-on authentication
@@ -28,10 +31,11 @@ const (
 
 // UserRequireCredentialsPolicy defines the credential policy for a user
 type UserRequireCredentialsPolicy struct {
-	HTTP     []CredentialKind `json:"http,omitempty"`
-	SSH      []CredentialKind `json:"ssh,omitempty"`
-	MySQL    []CredentialKind `json:"mysql,omitempty"`
-	Postgres []CredentialKind `json:"postgres,omitempty"`
+	HTTP       []CredentialKind `json:"http,omitempty"`
+	SSH        []CredentialKind `json:"ssh,omitempty"`
+	MySQL      []CredentialKind `json:"mysql,omitempty"`
+	Postgres   []CredentialKind `json:"postgres,omitempty"`
+	Kubernetes []CredentialKind `json:"kubernetes,omitempty"`
 }
 
 // User represents a Warpgate user
@@ -165,6 +169,22 @@ func (c *Client) AddPasswordCredential(ctx context.Context, userID string, passw
 	return &cred, nil
 }
 
+// GetPasswordCredentials retrieves all password credentials for a user. The
+// password hashes are never returned by the API, only the credential IDs.
+func (c *Client) GetPasswordCredentials(ctx context.Context, userID string) ([]PasswordCredential, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, fmt.Sprintf("/users/%s/credentials/passwords", userID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var creds []PasswordCredential
+	if err := handleResponse(resp, &creds); err != nil {
+		return nil, err
+	}
+
+	return creds, nil
+}
+
 // DeletePasswordCredential removes a password credential from a user.
 func (c *Client) DeletePasswordCredential(ctx context.Context, userID string, credentialID string) error {
 	resp, err := c.doRequest(ctx, http.MethodDelete, fmt.Sprintf("/users/%s/credentials/passwords/%s", userID, credentialID), nil)
@@ -249,6 +269,120 @@ func (c *Client) DeletePublicKeyCredential(ctx context.Context, userID string, c
 	return handleResponse(resp, nil)
 }
 
+// The certificate credential endpoints below and the csr_pem field of the issue
+// request are written for the admin API of Warpgate 0.17. They have not been
+// checked against the OpenAPI schema published with that release, so compare
+// them with it before relying on them with another Warpgate version.
+
+// CertificateCredential represents a client certificate credential for a user,
+// used to authenticate to Kubernetes targets
+type CertificateCredential struct {
+	ID             string `json:"id,omitempty"`
+	Label          string `json:"label"`
+	CertificatePEM string `json:"certificate_pem,omitempty"`
+	DateAdded      string `json:"date_added,omitempty"`
+	LastUsed       string `json:"last_used,omitempty"`
+}
+
+// CertificateCredentialIssueRequest is the request payload for having Warpgate
+// issue a certificate credential for a certificate signing request
+type CertificateCredentialIssueRequest struct {
+	Label  string `json:"label"`
+	CSRPEM string `json:"csr_pem"`
+}
+
+// AddCertificateCredential registers an existing PEM certificate as a
+// credential of the specified user, through the
+// POST /users/{id}/credentials/certificates endpoint of the admin API.
+// Certificate credentials are expected to have been added in Warpgate 0.17,
+// along with Kubernetes targets.
+func (c *Client) AddCertificateCredential(ctx context.Context, userID string, label, certificatePEM string) (*CertificateCredential, error) {
+	req := &CertificateCredential{
+		Label:          label,
+		CertificatePEM: certificatePEM,
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, fmt.Sprintf("/users/%s/credentials/certificates", userID), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var cred CertificateCredential
+	if err := handleResponse(resp, &cred); err != nil {
+		return nil, err
+	}
+
+	return &cred, nil
+}
+
+// IssueCertificateCredential has Warpgate sign the PEM certificate signing
+// request and adds the resulting certificate as a credential of the specified
+// user, through the POST /users/{id}/credentials/certificates/issue endpoint
+// of the admin API, expected to be available since Warpgate 0.17.
+func (c *Client) IssueCertificateCredential(ctx context.Context, userID string, label, csrPEM string) (*CertificateCredential, error) {
+	req := &CertificateCredentialIssueRequest{
+		Label:  label,
+		CSRPEM: csrPEM,
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, fmt.Sprintf("/users/%s/credentials/certificates/issue", userID), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var cred CertificateCredential
+	if err := handleResponse(resp, &cred); err != nil {
+		return nil, err
+	}
+
+	return &cred, nil
+}
+
+// GetCertificateCredentials retrieves all certificate credentials for a user.
+func (c *Client) GetCertificateCredentials(ctx context.Context, userID string) ([]CertificateCredential, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, fmt.Sprintf("/users/%s/credentials/certificates", userID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var creds []CertificateCredential
+	if err := handleResponse(resp, &creds); err != nil {
+		return nil, err
+	}
+
+	return creds, nil
+}
+
+// UpdateCertificateCredential changes the label of an existing certificate
+// credential. The certificate itself can't be changed.
+func (c *Client) UpdateCertificateCredential(ctx context.Context, userID string, credentialID string, label string) (*CertificateCredential, error) {
+	req := &CertificateCredential{
+		Label: label,
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPut, fmt.Sprintf("/users/%s/credentials/certificates/%s", userID, credentialID), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var cred CertificateCredential
+	if err := handleResponse(resp, &cred); err != nil {
+		return nil, err
+	}
+
+	return &cred, nil
+}
+
+// DeleteCertificateCredential removes a certificate credential from a user.
+func (c *Client) DeleteCertificateCredential(ctx context.Context, userID string, credentialID string) error {
+	resp, err := c.doRequest(ctx, http.MethodDelete, fmt.Sprintf("/users/%s/credentials/certificates/%s", userID, credentialID), nil)
+	if err != nil {
+		return err
+	}
+
+	return handleResponse(resp, nil)
+}
+
 // SsoCredential represents an SSO credential for a user
 type SsoCredential struct {
 	ID       string `json:"id,omitempty"`
@@ -320,3 +454,24 @@ func (c *Client) DeleteSsoCredential(ctx context.Context, userID string, credent
 
 	return handleResponse(resp, nil)
 }
+
+// OtpCredential represents a TOTP credential for a user. The secret key is
+// never returned by the API, only the credential ID.
+type OtpCredential struct {
+	ID string `json:"id,omitempty"`
+}
+
+// GetOtpCredentials retrieves all TOTP credentials for a user.
+func (c *Client) GetOtpCredentials(ctx context.Context, userID string) ([]OtpCredential, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, fmt.Sprintf("/users/%s/credentials/otp", userID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var creds []OtpCredential
+	if err := handleResponse(resp, &creds); err != nil {
+		return nil, err
+	}
+
+	return creds, nil
+}
@@ -0,0 +1,658 @@
+// Package export generates Terraform configuration for the objects of an existing
+// Warpgate instance, so that it can be brought under Terraform management.
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Result contains the generated configuration and any objects that could not be exported.
+type Result struct {
+	// Config is the generated HCL, including variable, resource and import blocks
+	Config []byte
+	// Warnings lists objects that were skipped or need manual attention
+	Warnings []string
+}
+
+// generator holds the state of a single export run.
+type generator struct {
+	client *client.Client
+
+	variables *hclwrite.Body
+	resources *hclwrite.Body
+	imports   *hclwrite.Body
+
+	// names tracks the resource names in use per resource type (or "var" for variables)
+	names map[string]map[string]bool
+
+	roles        map[string]string
+	users        map[string]string
+	targets      map[string]string
+	targetGroups map[string]string
+
+	warnings []string
+}
+
+// Generate reads all users, roles, targets, target groups, role bindings, credentials
+// and parameters from Warpgate and returns matching resource and import blocks.
+// Secrets are never written out; they are replaced by sensitive variables.
+// The built-in admin role and user are skipped, so that destroying the
+// generated configuration can't delete them.
+func Generate(ctx context.Context, c *client.Client) (*Result, error) {
+	variablesFile := hclwrite.NewEmptyFile()
+	resourcesFile := hclwrite.NewEmptyFile()
+	importsFile := hclwrite.NewEmptyFile()
+
+	g := &generator{
+		client:       c,
+		variables:    variablesFile.Body(),
+		resources:    resourcesFile.Body(),
+		imports:      importsFile.Body(),
+		names:        make(map[string]map[string]bool),
+		roles:        make(map[string]string),
+		users:        make(map[string]string),
+		targets:      make(map[string]string),
+		targetGroups: make(map[string]string),
+	}
+
+	steps := []func(context.Context) error{
+		g.exportRoles,
+		g.exportTargetGroups,
+		g.exportUsers,
+		g.exportTargets,
+		g.exportParameters,
+	}
+
+	for _, step := range steps {
+		if err := step(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var config []byte
+	for _, f := range []*hclwrite.File{variablesFile, resourcesFile, importsFile} {
+		if len(f.Body().Attributes()) == 0 && len(f.Body().Blocks()) == 0 {
+			continue
+		}
+		if len(config) > 0 {
+			config = append(config, '\n')
+		}
+		config = append(config, f.Bytes()...)
+	}
+
+	return &Result{
+		Config:   hclwrite.Format(config),
+		Warnings: g.warnings,
+	}, nil
+}
+
+// exportRoles generates warpgate_role resources.
+func (g *generator) exportRoles(ctx context.Context) error {
+	roles, err := g.client.GetRoles(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to list roles: %w", err)
+	}
+
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+
+	for _, role := range roles {
+		// Destroying the exported configuration would delete the built-in role
+		if role.Name == builtinAdminRole {
+			g.warnings = append(g.warnings, fmt.Sprintf("skipping built-in role %s", role.Name))
+			continue
+		}
+
+		name := g.resourceName("warpgate_role", role.Name)
+		g.roles[role.ID] = name
+
+		body := g.resource("warpgate_role", name, role.ID)
+		body.SetAttributeValue("name", cty.StringVal(role.Name))
+		setOptionalString(body, "description", role.Description)
+	}
+
+	return nil
+}
+
+// exportTargetGroups generates warpgate_target_group resources.
+func (g *generator) exportTargetGroups(ctx context.Context) error {
+	targetGroups, err := g.client.GetTargetGroups(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list target groups: %w", err)
+	}
+
+	sort.Slice(targetGroups, func(i, j int) bool { return targetGroups[i].Name < targetGroups[j].Name })
+
+	for _, targetGroup := range targetGroups {
+		name := g.resourceName("warpgate_target_group", targetGroup.Name)
+		g.targetGroups[targetGroup.ID] = name
+
+		body := g.resource("warpgate_target_group", name, targetGroup.ID)
+		body.SetAttributeValue("name", cty.StringVal(targetGroup.Name))
+		setOptionalString(body, "description", targetGroup.Description)
+		setOptionalString(body, "color", targetGroup.Color)
+	}
+
+	return nil
+}
+
+// exportUsers generates warpgate_user resources along with their role memberships
+// and credentials.
+func (g *generator) exportUsers(ctx context.Context) error {
+	users, err := g.client.GetUsers(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+
+	for _, user := range users {
+		roles, err := g.client.GetUserRoles(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get roles of user %s: %w", user.Username, err)
+		}
+
+		// Destroying the exported configuration would delete the admin user
+		// created when Warpgate was set up, and with it the admin access
+		if isBuiltinAdminUser(user, roles) {
+			g.warnings = append(g.warnings, fmt.Sprintf("skipping built-in user %s, along with its roles and credentials", user.Username))
+			continue
+		}
+
+		name := g.resourceName("warpgate_user", user.Username)
+		g.users[user.ID] = name
+
+		body := g.resource("warpgate_user", name, user.ID)
+		body.SetAttributeValue("username", cty.StringVal(user.Username))
+		setOptionalString(body, "description", user.Description)
+
+		if user.AllowedIPRanges != nil && len(*user.AllowedIPRanges) > 0 {
+			body.SetAttributeValue("allowed_ip_ranges", stringList(*user.AllowedIPRanges))
+		}
+
+		if policy := user.CredentialPolicy; !isEmptyCredentialPolicy(policy) {
+			policyBody := body.AppendNewBlock("credential_policy", nil).Body()
+			setCredentialKinds(policyBody, "http", policy.HTTP)
+			setCredentialKinds(policyBody, "ssh", policy.SSH)
+			setCredentialKinds(policyBody, "mysql", policy.MySQL)
+			setCredentialKinds(policyBody, "postgres", policy.Postgres)
+			setCredentialKinds(policyBody, "kubernetes", policy.Kubernetes)
+		}
+
+		g.exportUserRoles(user, name, roles)
+
+		if err := g.exportUserCredentials(ctx, user, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exportUserRoles generates a warpgate_user_roles resource for a user with roles.
+func (g *generator) exportUserRoles(user client.User, userName string, roles []client.Role) {
+	if len(roles) == 0 {
+		return
+	}
+
+	name := g.resourceName("warpgate_user_roles", userName)
+	body := g.resource("warpgate_user_roles", name, user.ID)
+	body.SetAttributeTraversal("user_id", reference("warpgate_user", userName))
+	body.SetAttributeRaw("role_ids", g.roleReferences(roles))
+}
+
+// builtinAdminRole is the role Warpgate creates for its administrators.
+const builtinAdminRole = "warpgate:admin"
+
+// isBuiltinAdminUser reports whether a user is the admin user Warpgate creates
+// during setup.
+func isBuiltinAdminUser(user client.User, roles []client.Role) bool {
+	if user.Username != "admin" {
+		return false
+	}
+
+	for _, role := range roles {
+		if role.Name == builtinAdminRole {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptyCredentialPolicy reports whether a credential policy is missing or
+// requires no credential for any protocol.
+func isEmptyCredentialPolicy(policy *client.UserRequireCredentialsPolicy) bool {
+	return policy == nil ||
+		len(policy.HTTP) == 0 && len(policy.SSH) == 0 && len(policy.MySQL) == 0 && len(policy.Postgres) == 0 && len(policy.Kubernetes) == 0
+}
+
+// exportUserCredentials generates credential resources for a user. Passwords are
+// replaced by write-only variables and changes to their version ignored, so
+// that importing does not rotate them.
+func (g *generator) exportUserCredentials(ctx context.Context, user client.User, userName string) error {
+	passwords, err := g.client.GetPasswordCredentials(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get password credentials of user %s: %w", user.Username, err)
+	}
+
+	for _, cred := range passwords {
+		name := g.resourceName("warpgate_password_credential", userName)
+		body := g.resource("warpgate_password_credential", name, user.ID+":"+cred.ID)
+		body.SetAttributeTraversal("user_id", reference("warpgate_user", userName))
+		body.SetAttributeTraversal("password_wo", g.variable(name+"_password", "Password of Warpgate user "+user.Username))
+		body.SetAttributeValue("password_wo_version", cty.NumberIntVal(1))
+
+		lifecycle := body.AppendNewBlock("lifecycle", nil).Body()
+		lifecycle.SetAttributeRaw("ignore_changes", hclwrite.TokensForTuple([]hclwrite.Tokens{
+			hclwrite.TokensForIdentifier("password_wo_version"),
+		}))
+	}
+
+	publicKeys, err := g.client.GetPublicKeyCredentials(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get public key credentials of user %s: %w", user.Username, err)
+	}
+
+	for _, cred := range publicKeys {
+		name := g.resourceName("warpgate_public_key_credential", userName+"_"+cred.Label)
+		body := g.resource("warpgate_public_key_credential", name, user.ID+":"+cred.ID)
+		body.SetAttributeTraversal("user_id", reference("warpgate_user", userName))
+		body.SetAttributeValue("label", cty.StringVal(cred.Label))
+		body.SetAttributeValue("public_key", cty.StringVal(cred.OpensshPublicKey))
+	}
+
+	certificates, err := g.client.GetCertificateCredentials(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get certificate credentials of user %s: %w", user.Username, err)
+	}
+
+	for _, cred := range certificates {
+		name := g.resourceName("warpgate_certificate_credential", userName+"_"+cred.Label)
+		body := g.resource("warpgate_certificate_credential", name, user.ID+":"+cred.ID)
+		body.SetAttributeTraversal("user_id", reference("warpgate_user", userName))
+		body.SetAttributeValue("label", cty.StringVal(cred.Label))
+		body.SetAttributeValue("certificate", cty.StringVal(cred.CertificatePEM))
+	}
+
+	ssoCredentials, err := g.client.GetSsoCredentials(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get SSO credentials of user %s: %w", user.Username, err)
+	}
+
+	for _, cred := range ssoCredentials {
+		name := g.resourceName("warpgate_user_sso_credential", userName+"_"+cred.Provider)
+		body := g.resource("warpgate_user_sso_credential", name, user.ID+":"+cred.ID)
+		body.SetAttributeTraversal("user_id", reference("warpgate_user", userName))
+		body.SetAttributeValue("sso_provider", cty.StringVal(cred.Provider))
+		body.SetAttributeValue("email", cty.StringVal(cred.Email))
+	}
+
+	return nil
+}
+
+// exportTargets generates warpgate_target resources along with their role sets.
+func (g *generator) exportTargets(ctx context.Context) error {
+	targets, err := g.client.GetTargets(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to list targets: %w", err)
+	}
+
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Name < targets[j].Name })
+
+	for _, target := range targets {
+		options, err := optionsToMap(target.Options)
+		if err != nil {
+			return fmt.Errorf("failed to read options of target %s: %w", target.Name, err)
+		}
+
+		kind, _ := options["kind"].(string)
+		if !isManagedTargetKind(kind) {
+			// Built-in targets such as the Warpgate web admin can't be managed
+			g.warnings = append(g.warnings, fmt.Sprintf("skipping target %s of unsupported kind %q", target.Name, kind))
+			continue
+		}
+
+		name := g.resourceName("warpgate_target", target.Name)
+		g.targets[target.ID] = name
+
+		body := g.resource("warpgate_target", name, target.ID)
+		body.SetAttributeValue("name", cty.StringVal(target.Name))
+		setOptionalString(body, "description", target.Description)
+
+		if target.GroupId != "" {
+			if groupName, ok := g.targetGroups[target.GroupId]; ok {
+				body.SetAttributeTraversal("group_id", reference("warpgate_target_group", groupName))
+			} else {
+				body.SetAttributeValue("group_id", cty.StringVal(target.GroupId))
+			}
+		}
+
+		if err := g.setTargetOptions(body, name, target.Name, kind, options); err != nil {
+			return fmt.Errorf("failed to export options of target %s: %w", target.Name, err)
+		}
+
+		roles, err := g.client.GetTargetRoles(ctx, target.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get roles of target %s: %w", target.Name, err)
+		}
+
+		if len(roles) > 0 {
+			rolesName := g.resourceName("warpgate_target_roles", name)
+			rolesBody := g.resource("warpgate_target_roles", rolesName, target.ID)
+			rolesBody.SetAttributeTraversal("target_id", reference("warpgate_target", name))
+			rolesBody.SetAttributeRaw("role_ids", g.roleReferences(roles))
+		}
+	}
+
+	return nil
+}
+
+// isManagedTargetKind reports whether targets of the given kind can be managed by the provider.
+func isManagedTargetKind(kind string) bool {
+	switch kind {
+	case "Ssh", "Http", "MySql", "Postgres", "Kubernetes":
+		return true
+	default:
+		return false
+	}
+}
+
+// setTargetOptions writes the options block matching the target kind. Passwords,
+// tokens and private keys are replaced by variables.
+func (g *generator) setTargetOptions(body *hclwrite.Body, resourceName, targetName, kind string, options map[string]any) error {
+	switch kind {
+	case "Ssh":
+		opts := body.AppendNewBlock("ssh_options", nil).Body()
+		opts.SetAttributeValue("host", cty.StringVal(stringField(options, "host")))
+		opts.SetAttributeValue("port", cty.NumberIntVal(intField(options, "port")))
+		opts.SetAttributeValue("username", cty.StringVal(stringField(options, "username")))
+		if allowInsecureAlgos, _ := options["allow_insecure_algos"].(bool); allowInsecureAlgos {
+			opts.SetAttributeValue("allow_insecure_algos", cty.True)
+		}
+
+		auth, _ := options["auth"].(map[string]any)
+		switch authKind := stringField(auth, "kind"); authKind {
+		case "Password":
+			passwordAuth := opts.AppendNewBlock("password_auth", nil).Body()
+			passwordAuth.SetAttributeTraversal("password", g.variable(resourceName+"_password", "SSH password of Warpgate target "+targetName))
+		case "PublicKey":
+			opts.AppendNewBlock("public_key_auth", nil)
+		default:
+			return fmt.Errorf("unknown SSH auth kind: %s", authKind)
+		}
+
+	case "Http":
+		opts := body.AppendNewBlock("http_options", nil).Body()
+		opts.SetAttributeValue("url", cty.StringVal(stringField(options, "url")))
+		setTLS(opts, options)
+
+		if headers, ok := options["headers"].(map[string]any); ok && len(headers) > 0 {
+			values := make(map[string]cty.Value, len(headers))
+			for k, v := range headers {
+				s, _ := v.(string)
+				values[k] = cty.StringVal(s)
+			}
+			opts.SetAttributeValue("headers", cty.MapVal(values))
+		}
+
+		setOptionalString(opts, "external_host", stringField(options, "external_host"))
+
+	case "MySql", "Postgres":
+		blockName, label := "mysql_options", "MySQL"
+		if kind == "Postgres" {
+			blockName, label = "postgres_options", "PostgreSQL"
+		}
+
+		opts := body.AppendNewBlock(blockName, nil).Body()
+		opts.SetAttributeValue("host", cty.StringVal(stringField(options, "host")))
+		opts.SetAttributeValue("port", cty.NumberIntVal(intField(options, "port")))
+		opts.SetAttributeValue("username", cty.StringVal(stringField(options, "username")))
+
+		if kind == "Postgres" {
+			setOptionalString(opts, "protocol_version", stringField(options, "protocol_version"))
+		}
+
+		if hasPassword(options) {
+			opts.SetAttributeTraversal("password", g.variable(resourceName+"_password", label+" password of Warpgate target "+targetName))
+		}
+
+		setTLS(opts, options)
+
+	case "Kubernetes":
+		opts := body.AppendNewBlock("kubernetes_options", nil).Body()
+		opts.SetAttributeValue("cluster_url", cty.StringVal(stringField(options, "cluster_url")))
+		setTLS(opts, options)
+
+		auth, _ := options["auth"].(map[string]any)
+		switch authKind := stringField(auth, "kind"); authKind {
+		case "Token":
+			tokenAuth := opts.AppendNewBlock("token_auth", nil).Body()
+			tokenAuth.SetAttributeTraversal("token", g.variable(resourceName+"_token", "Kubernetes token of Warpgate target "+targetName))
+		case "Certificate":
+			certificateAuth := opts.AppendNewBlock("certificate_auth", nil).Body()
+			certificateAuth.SetAttributeValue("certificate", cty.StringVal(stringField(auth, "certificate")))
+			certificateAuth.SetAttributeTraversal("private_key", g.variable(resourceName+"_private_key", "Kubernetes client private key of Warpgate target "+targetName))
+		default:
+			return fmt.Errorf("unknown Kubernetes auth kind: %s", authKind)
+		}
+	}
+
+	return nil
+}
+
+// exportParameters generates the warpgate_parameters singleton resource.
+func (g *generator) exportParameters(ctx context.Context) error {
+	params, err := g.client.GetParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read parameters: %w", err)
+	}
+
+	body := g.resource("warpgate_parameters", "this", "parameters")
+	body.SetAttributeValue("allow_own_credential_management", cty.BoolVal(params.AllowOwnCredentialManagement))
+	setOptionalInt(body, "rate_limit_bytes_per_second", int64(params.RateLimitBytesPerSecond))
+	body.SetAttributeValue("ssh_client_auth_publickey", cty.BoolVal(params.SSHClientAuthPublickey))
+	body.SetAttributeValue("ssh_client_auth_password", cty.BoolVal(params.SSHClientAuthPassword))
+	body.SetAttributeValue("ssh_client_auth_keyboard_interactive", cty.BoolVal(params.SSHClientAuthKeyboardInteractive))
+	body.SetAttributeValue("minimize_password_login", cty.BoolVal(params.MinimizePasswordLogin))
+	body.SetAttributeValue("ticket_self_service_enabled", cty.BoolVal(params.TicketSelfServiceEnabled))
+	body.SetAttributeValue("ticket_auto_approve_existing_access", cty.BoolVal(params.TicketAutoApproveExistingAccess))
+	setOptionalInt(body, "ticket_max_duration_seconds", params.TicketMaxDurationSeconds)
+	setOptionalInt(body, "ticket_max_uses", int64(params.TicketMaxUses))
+	body.SetAttributeValue("ticket_require_description", cty.BoolVal(params.TicketRequireDescription))
+	body.SetAttributeValue("ticket_request_show_all_targets", cty.BoolVal(params.TicketRequestShowAllTargets))
+	setOptionalString(body, "target_click_action", params.TargetClickAction)
+	body.SetAttributeValue("show_session_menu", cty.BoolVal(params.ShowSessionMenu))
+	setOptionalInt(body, "max_api_token_duration_seconds", params.MaxAPITokenDurationSeconds)
+	body.SetAttributeValue("record_scp", cty.BoolVal(params.RecordSCP))
+
+	return nil
+}
+
+// resource appends a resource block and a matching import block, returning the
+// body of the resource block.
+func (g *generator) resource(resourceType, name, id string) *hclwrite.Body {
+	if len(g.resources.Blocks()) > 0 {
+		g.resources.AppendNewline()
+	}
+	block := g.resources.AppendNewBlock("resource", []string{resourceType, name})
+
+	if len(g.imports.Blocks()) > 0 {
+		g.imports.AppendNewline()
+	}
+	importBody := g.imports.AppendNewBlock("import", nil).Body()
+	importBody.SetAttributeTraversal("to", hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceType},
+		hcl.TraverseAttr{Name: name},
+	})
+	importBody.SetAttributeValue("id", cty.StringVal(id))
+
+	return block.Body()
+}
+
+// variable declares a sensitive string variable and returns a reference to it.
+func (g *generator) variable(name, description string) hcl.Traversal {
+	name = g.resourceName("var", name)
+
+	if len(g.variables.Blocks()) > 0 {
+		g.variables.AppendNewline()
+	}
+	body := g.variables.AppendNewBlock("variable", []string{name}).Body()
+	body.SetAttributeValue("description", cty.StringVal(description))
+	body.SetAttributeTraversal("type", hcl.Traversal{hcl.TraverseRoot{Name: "string"}})
+	body.SetAttributeValue("sensitive", cty.True)
+
+	return hcl.Traversal{
+		hcl.TraverseRoot{Name: "var"},
+		hcl.TraverseAttr{Name: name},
+	}
+}
+
+// roleReferences returns a tuple of references to the exported roles, falling back
+// to the literal role ID for roles that were not exported.
+func (g *generator) roleReferences(roles []client.Role) hclwrite.Tokens {
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+
+	elems := make([]hclwrite.Tokens, len(roles))
+	for i, role := range roles {
+		if name, ok := g.roles[role.ID]; ok {
+			elems[i] = hclwrite.TokensForTraversal(reference("warpgate_role", name))
+		} else {
+			elems[i] = hclwrite.TokensForValue(cty.StringVal(role.ID))
+		}
+	}
+
+	return hclwrite.TokensForTuple(elems)
+}
+
+var invalidIdentifierChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// resourceName derives a unique Terraform identifier from a Warpgate object name.
+func (g *generator) resourceName(resourceType, name string) string {
+	base := strings.Trim(invalidIdentifierChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if base == "" {
+		base = "unnamed"
+	}
+	if base[0] >= '0' && base[0] <= '9' {
+		base = "_" + base
+	}
+
+	used, ok := g.names[resourceType]
+	if !ok {
+		used = make(map[string]bool)
+		g.names[resourceType] = used
+	}
+
+	candidate := base
+	for i := 2; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s_%d", base, i)
+	}
+	used[candidate] = true
+
+	return candidate
+}
+
+// reference returns a traversal to the ID of a resource.
+func reference(resourceType, name string) hcl.Traversal {
+	return hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceType},
+		hcl.TraverseAttr{Name: name},
+		hcl.TraverseAttr{Name: "id"},
+	}
+}
+
+// setOptionalString sets a string attribute unless the value is empty.
+func setOptionalString(body *hclwrite.Body, name, value string) {
+	if value != "" {
+		body.SetAttributeValue(name, cty.StringVal(value))
+	}
+}
+
+// setOptionalInt sets a number attribute unless the value is zero.
+func setOptionalInt(body *hclwrite.Body, name string, value int64) {
+	if value != 0 {
+		body.SetAttributeValue(name, cty.NumberIntVal(value))
+	}
+}
+
+// setCredentialKinds sets a credential policy list unless it is empty.
+func setCredentialKinds(body *hclwrite.Body, name string, kinds []client.CredentialKind) {
+	if len(kinds) == 0 {
+		return
+	}
+
+	values := make([]string, len(kinds))
+	for i, kind := range kinds {
+		values[i] = string(kind)
+	}
+	body.SetAttributeValue(name, stringList(values))
+}
+
+// setTLS writes the tls block of a target options block.
+func setTLS(body *hclwrite.Body, options map[string]any) {
+	tls, _ := options["tls"].(map[string]any)
+	verify, _ := tls["verify"].(bool)
+
+	tlsBody := body.AppendNewBlock("tls", nil).Body()
+	tlsBody.SetAttributeValue("mode", cty.StringVal(stringField(tls, "mode")))
+	tlsBody.SetAttributeValue("verify", cty.BoolVal(verify))
+}
+
+// hasPassword reports whether database target options carry a password, either
+// directly or in a password auth block.
+func hasPassword(options map[string]any) bool {
+	if stringField(options, "password") != "" {
+		return true
+	}
+
+	auth, _ := options["auth"].(map[string]any)
+	return stringField(auth, "kind") == "Password" && stringField(auth, "password") != ""
+}
+
+// stringList converts a string slice to a cty list value.
+func stringList(values []string) cty.Value {
+	if len(values) == 0 {
+		return cty.ListValEmpty(cty.String)
+	}
+
+	list := make([]cty.Value, len(values))
+	for i, v := range values {
+		list[i] = cty.StringVal(v)
+	}
+	return cty.ListVal(list)
+}
+
+// stringField returns a string field of a decoded JSON object, or "" if absent.
+func stringField(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+// intField returns a numeric field of a decoded JSON object, or 0 if absent.
+func intField(m map[string]any, key string) int64 {
+	f, _ := m[key].(float64)
+	return int64(f)
+}
+
+// optionsToMap converts target options as returned by the client to a generic map.
+func optionsToMap(options client.TargetOptions) (map[string]any, error) {
+	jsonData, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
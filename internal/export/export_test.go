@@ -0,0 +1,185 @@
+package export
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/warpgatetest"
+)
+
+const (
+	testToken     = "test-token"
+	testPublicKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGrT32oEeYONwNUfLpFLVUoNJN2Kr+RTU4ULdPkeuS7i"
+)
+
+func TestGenerate(t *testing.T) {
+	s := warpgatetest.NewServer(testToken)
+	t.Cleanup(s.Close)
+
+	c, err := client.NewClient(&client.Config{Host: client.AdminAPIURL(s.URL), Token: testToken})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	role, err := c.CreateRole(ctx, &client.RoleCreateRequest{Name: "Dev Ops"})
+	if err != nil {
+		t.Fatalf("failed to create role: %v", err)
+	}
+
+	// The built-in admin role and user are left out
+	adminRole, err := c.CreateRole(ctx, &client.RoleCreateRequest{Name: "warpgate:admin"})
+	if err != nil {
+		t.Fatalf("failed to create role: %v", err)
+	}
+	admin, err := c.CreateUser(ctx, &client.UserCreateRequest{Username: "admin"})
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	if err := c.AddUserRole(ctx, admin.ID, adminRole.ID); err != nil {
+		t.Fatalf("failed to assign role: %v", err)
+	}
+
+	// Usernames that sanitise to the same resource name, or to one starting
+	// with a digit
+	var users []*client.User
+	for _, username := range []string{"alice.smith", "Alice Smith", "1bot"} {
+		user, err := c.CreateUser(ctx, &client.UserCreateRequest{Username: username})
+		if err != nil {
+			t.Fatalf("failed to create user %s: %v", username, err)
+		}
+		users = append(users, user)
+	}
+
+	// Users are exported in username order, so "Alice Smith" comes first
+	alice := users[1]
+
+	// A policy without any required credential isn't written out
+	if _, err := c.UpdateUser(ctx, users[2].ID, &client.UserUpdateRequest{
+		Username:         users[2].Username,
+		CredentialPolicy: &client.UserRequireCredentialsPolicy{},
+	}); err != nil {
+		t.Fatalf("failed to update user: %v", err)
+	}
+
+	if err := c.AddUserRole(ctx, alice.ID, role.ID); err != nil {
+		t.Fatalf("failed to assign role: %v", err)
+	}
+	if _, err := c.AddPasswordCredential(ctx, alice.ID, "hunter2-user-password"); err != nil {
+		t.Fatalf("failed to add password: %v", err)
+	}
+	if _, err := c.AddPublicKeyCredential(ctx, alice.ID, "laptop", testPublicKey); err != nil {
+		t.Fatalf("failed to add public key: %v", err)
+	}
+
+	target, err := c.CreateTarget(ctx, &client.TargetDataRequest{
+		Name: "web server",
+		Options: client.TargetSSHOptions{
+			Kind:     "Ssh",
+			Host:     "10.0.0.1",
+			Port:     22,
+			Username: "root",
+			Auth:     client.SSHTargetPasswordAuth{Kind: "Password", Password: "hunter2-target-password"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create target: %v", err)
+	}
+	if err := c.AddTargetRole(ctx, target.ID, role.ID); err != nil {
+		t.Fatalf("failed to assign target role: %v", err)
+	}
+
+	result, err := Generate(ctx, c)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	config := string(result.Config)
+
+	file, diags := hclsyntax.ParseConfig(result.Config, "export.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("generated configuration doesn't parse: %v\n%s", diags, config)
+	}
+
+	// Every resource has a matching import block
+	resources := map[string]bool{}
+	imports := map[string]bool{}
+	for _, block := range file.Body.(*hclsyntax.Body).Blocks {
+		switch block.Type {
+		case "resource":
+			resources[block.Labels[0]+"."+block.Labels[1]] = true
+		case "import":
+			to, diags := hcl.AbsTraversalForExpr(block.Body.Attributes["to"].Expr)
+			if diags.HasErrors() {
+				t.Fatalf("invalid import target: %v", diags)
+			}
+			imports[to.RootName()+"."+to[1].(hcl.TraverseAttr).Name] = true
+		}
+	}
+
+	for _, address := range []string{
+		"warpgate_role.dev_ops",
+		"warpgate_user.alice_smith",
+		"warpgate_user.alice_smith_2",
+		"warpgate_user._1bot",
+		"warpgate_user_roles.alice_smith",
+		"warpgate_password_credential.alice_smith",
+		"warpgate_public_key_credential.alice_smith_laptop",
+		"warpgate_target.web_server",
+		"warpgate_target_roles.web_server",
+		"warpgate_parameters.this",
+	} {
+		if !resources[address] {
+			t.Errorf("expected resource %s in:\n%s", address, config)
+		}
+		if !imports[address] {
+			t.Errorf("expected an import block for %s", address)
+		}
+	}
+	if len(resources) != len(imports) {
+		t.Errorf("expected as many import blocks as resources, got %d imports for %d resources", len(imports), len(resources))
+	}
+
+	for _, pattern := range []string{
+		`id\s*=\s*"` + alice.ID + `:[^"]+"`,
+		`role_ids\s*=\s*\[warpgate_role\.dev_ops\.id\]`,
+		`user_id\s*=\s*warpgate_user\.alice_smith\.id`,
+		`public_key\s*=\s*"` + regexp.QuoteMeta(testPublicKey) + `"`,
+		`password_wo\s*=\s*var\.alice_smith_password`,
+		`password_wo_version\s*=\s*1`,
+		`ignore_changes\s*=\s*\[password_wo_version\]`,
+		`password\s*=\s*var\.web_server_password`,
+		`variable "web_server_password" \{[^}]*sensitive\s*=\s*true`,
+	} {
+		if !regexp.MustCompile(pattern).MatchString(config) {
+			t.Errorf("expected the configuration to match %s:\n%s", pattern, config)
+		}
+	}
+
+	for _, unexpected := range []string{"warpgate:admin", `"admin"`, "credential_policy"} {
+		if strings.Contains(config, unexpected) {
+			t.Errorf("the configuration unexpectedly contains %s:\n%s", unexpected, config)
+		}
+	}
+
+	expectedWarnings := []string{
+		"skipping built-in role warpgate:admin",
+		"skipping built-in user admin, along with its roles and credentials",
+	}
+	if strings.Join(result.Warnings, "\n") != strings.Join(expectedWarnings, "\n") {
+		t.Errorf("expected warnings %q, got %q", expectedWarnings, result.Warnings)
+	}
+
+	// Secrets are replaced by variables and never written out
+	for _, secret := range []string{"hunter2-user-password", "hunter2-target-password"} {
+		if strings.Contains(config, secret) {
+			t.Errorf("the configuration contains the secret %q", secret)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceRole(t *testing.T) {
+	name := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "warpgate_role" "test" {
+  name        = %q
+  description = "Test role"
+}
+
+data "warpgate_role" "by_id" {
+  id = warpgate_role.test.id
+}
+
+data "warpgate_role" "by_name" {
+  name = warpgate_role.test.name
+}
+`, name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.warpgate_role.by_id", "name", name),
+					resource.TestCheckResourceAttr("data.warpgate_role.by_id", "description", "Test role"),
+					resource.TestCheckResourceAttrPair("data.warpgate_role.by_name", "id", "warpgate_role.test", "id"),
+					resource.TestCheckResourceAttr("data.warpgate_role.by_name", "description", "Test role"),
+				),
+			},
+		},
+	})
+}
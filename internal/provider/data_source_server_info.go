@@ -0,0 +1,132 @@
+// Package provider implements the Terraform provider for Warpgate
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dataSourceServerInfo creates and returns a schema for the server info data source.
+func dataSourceServerInfo() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceServerInfoRead,
+		Description: "Retrieves information about the Warpgate server, such as its version and the protocols it serves.",
+		Schema: map[string]*schema.Schema{
+			"version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The Warpgate version as reported by the server. Empty if the server does not report it.",
+			},
+			"external_host": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The external host name configured on the server",
+			},
+			"protocols": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The enabled protocols (ssh, http, mysql, postgres, kubernetes)",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"ssh_port": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The SSH listener port, or 0 if SSH is disabled",
+			},
+			"http_port": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The HTTP listener port, or 0 if HTTP is disabled",
+			},
+			"mysql_port": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The MySQL listener port, or 0 if MySQL is disabled",
+			},
+			"postgres_port": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The PostgreSQL listener port, or 0 if PostgreSQL is disabled",
+			},
+			"kubernetes_port": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The Kubernetes listener port, or 0 if Kubernetes is disabled",
+			},
+		},
+	}
+}
+
+// dataSourceServerInfoRead populates the Terraform state with the server info
+// retrieved when the provider was configured, querying the server again only
+// if that failed.
+func dataSourceServerInfoRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	var diags diag.Diagnostics
+
+	info := providerMeta.serverInfo
+	if info == nil {
+		var err error
+		info, err = c.GetServerInfo(ctx)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("failed to read server info: %w", err))
+		}
+	}
+
+	// Use a static ID since there is only one server
+	d.SetId("server-info")
+
+	version := ""
+	if info.Version != nil {
+		version = *info.Version
+	}
+	if err := d.Set("version", version); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set version: %w", err))
+	}
+
+	externalHost := ""
+	if info.ExternalHost != nil {
+		externalHost = *info.ExternalHost
+	}
+	if err := d.Set("external_host", externalHost); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set external_host: %w", err))
+	}
+
+	ports := []struct {
+		protocol  string
+		attribute string
+		port      *int
+	}{
+		{"ssh", "ssh_port", info.Ports.SSH},
+		{"http", "http_port", info.Ports.HTTP},
+		{"mysql", "mysql_port", info.Ports.MySQL},
+		{"postgres", "postgres_port", info.Ports.Postgres},
+		{"kubernetes", "kubernetes_port", info.Ports.Kubernetes},
+	}
+
+	protocols := []string{}
+	for _, p := range ports {
+		port := 0
+		if p.port != nil {
+			port = *p.port
+			protocols = append(protocols, p.protocol)
+		}
+
+		if err := d.Set(p.attribute, port); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to set %s: %w", p.attribute, err))
+		}
+	}
+
+	if err := d.Set("protocols", protocols); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set protocols: %w", err))
+	}
+
+	return diags
+}
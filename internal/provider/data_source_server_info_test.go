@@ -0,0 +1,23 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceServerInfo(t *testing.T) {
+	testAccTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "warpgate_server_info" "test" {}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.warpgate_server_info.test", "version"),
+					resource.TestCheckResourceAttrSet("data.warpgate_server_info.test", "protocols.#"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,24 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceSSHOwnKeys(t *testing.T) {
+	testAccTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "warpgate_ssh_own_keys" "test" {}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.warpgate_ssh_own_keys.test", "keys.#"),
+					resource.TestCheckResourceAttrSet("data.warpgate_ssh_own_keys.test", "keys.0.kind"),
+					resource.TestCheckResourceAttrSet("data.warpgate_ssh_own_keys.test", "keys.0.public_key_base64"),
+				),
+			},
+		},
+	})
+}
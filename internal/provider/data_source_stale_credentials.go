@@ -0,0 +1,308 @@
+// Package provider implements the Terraform provider for Warpgate
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+	"golang.org/x/crypto/ssh"
+)
+
+// usageCredentialKinds are the credential kinds for which Warpgate records
+// when they were last used.
+var usageCredentialKinds = []string{
+	string(client.CredentialKindPublicKey),
+	string(client.CredentialKindCertificate),
+}
+
+// dataSourceStaleCredentials creates and returns a schema for the stale
+// credentials data source.
+func dataSourceStaleCredentials() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceStaleCredentialsRead,
+		Description: "Reports the credentials of all users that haven't been used for a given duration, for key cleanup and access reviews.",
+		Schema: map[string]*schema.Schema{
+			"unused_for": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validatePositiveDuration,
+				Description:  "Report credentials that haven't been used for longer than this duration (e.g. `2160h`)",
+			},
+			"include_never_used": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Report credentials that were never used and were added longer than `unused_for` ago",
+			},
+			"credential_kinds": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The kinds of credentials to report, `PublicKey` and `Certificate`. Defaults to both.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(usageCredentialKinds, false),
+				},
+			},
+			"credentials": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The stale credentials, sorted by username, kind and label",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the user the credential belongs to",
+						},
+						"username": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The username of the user the credential belongs to",
+						},
+						"credential_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the credential",
+						},
+						"kind": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The kind of the credential, `PublicKey` or `Certificate`",
+						},
+						"label": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The label of the credential",
+						},
+						"fingerprint_sha256": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The SHA-256 fingerprint of public keys, empty for other kinds",
+						},
+						"date_added": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The date the credential was added",
+						},
+						"last_used": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The date the credential was last used, empty if it was never used",
+						},
+						"never_used": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the credential was never used",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// staleCredential is a credential of a user that hasn't been used recently.
+type staleCredential struct {
+	UserID      string
+	Username    string
+	ID          string
+	Kind        client.CredentialKind
+	Label       string
+	Fingerprint string
+	DateAdded   string
+	LastUsed    string
+}
+
+// isStaleCredential reports whether a credential was last used before cutoff.
+// Credentials that were never used are stale if they were added before cutoff
+// and never used credentials are included.
+func isStaleCredential(dateAdded, lastUsed string, cutoff time.Time, includeNeverUsed bool) (bool, error) {
+	if lastUsed == "" {
+		if !includeNeverUsed {
+			return false, nil
+		}
+
+		added, err := time.Parse(time.RFC3339Nano, dateAdded)
+		if err != nil {
+			return false, fmt.Errorf("invalid date_added %q: %w", dateAdded, err)
+		}
+		return added.Before(cutoff), nil
+	}
+
+	used, err := time.Parse(time.RFC3339Nano, lastUsed)
+	if err != nil {
+		return false, fmt.Errorf("invalid last_used %q: %w", lastUsed, err)
+	}
+	return used.Before(cutoff), nil
+}
+
+// filterStaleCredentials returns the stale credentials among those of a user.
+// A credential whose dates can't be parsed is skipped with a warning, so that
+// it doesn't hide the others.
+func filterStaleCredentials(userID, username string, candidates []staleCredential, cutoff time.Time, includeNeverUsed bool) ([]staleCredential, diag.Diagnostics) {
+	var stale []staleCredential
+	var diags diag.Diagnostics
+
+	for _, cred := range candidates {
+		ok, err := isStaleCredential(cred.DateAdded, cred.LastUsed, cutoff, includeNeverUsed)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Skipped %s credential %s of user %s", cred.Kind, cred.Label, username),
+				Detail:   fmt.Sprintf("Whether credential %s is stale can't be determined: %s.", cred.ID, err),
+			})
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		cred.UserID = userID
+		cred.Username = username
+		stale = append(stale, cred)
+	}
+
+	return stale, diags
+}
+
+// dataSourceStaleCredentialsRead scans the credentials of all users and
+// populates the Terraform state with the stale ones.
+func dataSourceStaleCredentialsRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	var diags diag.Diagnostics
+
+	unusedFor, err := time.ParseDuration(d.Get("unused_for").(string))
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("invalid unused_for: %w", err))
+	}
+	cutoff := time.Now().Add(-unusedFor)
+	includeNeverUsed := d.Get("include_never_used").(bool)
+
+	kinds := expandStringSet(d.Get("credential_kinds").(*schema.Set))
+	if len(kinds) == 0 {
+		kinds = usageCredentialKinds
+	}
+
+	scan := map[client.CredentialKind]bool{}
+	for _, kind := range kinds {
+		scan[client.CredentialKind(kind)] = true
+	}
+
+	users, err := c.GetUsers(ctx, "")
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to list users: %w", err))
+	}
+
+	var stale []staleCredential
+	for _, user := range users {
+		var candidates []staleCredential
+
+		if scan[client.CredentialKindPublicKey] {
+			creds, err := c.GetPublicKeyCredentials(ctx, user.ID)
+			if err != nil {
+				return diag.FromErr(fmt.Errorf("failed to get public key credentials of user %s: %w", user.Username, err))
+			}
+
+			for _, cred := range creds {
+				fingerprint := ""
+				if key, err := parsePublicKey(cred.OpensshPublicKey); err == nil {
+					fingerprint = ssh.FingerprintSHA256(key)
+				}
+
+				candidates = append(candidates, staleCredential{
+					ID:          cred.ID,
+					Kind:        client.CredentialKindPublicKey,
+					Label:       cred.Label,
+					Fingerprint: fingerprint,
+					DateAdded:   cred.DateAdded,
+					LastUsed:    cred.LastUsed,
+				})
+			}
+		}
+
+		if scan[client.CredentialKindCertificate] {
+			creds, err := c.GetCertificateCredentials(ctx, user.ID)
+			if err != nil {
+				// Certificate credentials are expected to be missing on older
+				// servers. They are skipped silently unless they were asked for.
+				featureErr := checkServerFeature(meta, featureCertificateCredentials)
+				if featureErr == nil {
+					return diag.FromErr(fmt.Errorf("failed to get certificate credentials of user %s: %w", user.Username, err))
+				}
+
+				delete(scan, client.CredentialKindCertificate)
+
+				if _, ok := d.GetOk("credential_kinds"); ok {
+					diags = append(diags, diag.Diagnostic{
+						Severity:      diag.Warning,
+						Summary:       "Certificate credentials are not reported",
+						Detail:        fmt.Sprintf("Listing certificate credentials failed, and %s: %s.", featureErr, err),
+						AttributePath: cty.GetAttrPath("credential_kinds"),
+					})
+				}
+			}
+
+			for _, cred := range creds {
+				candidates = append(candidates, staleCredential{
+					ID:        cred.ID,
+					Kind:      client.CredentialKindCertificate,
+					Label:     cred.Label,
+					DateAdded: cred.DateAdded,
+					LastUsed:  cred.LastUsed,
+				})
+			}
+		}
+
+		userStale, userDiags := filterStaleCredentials(user.ID, user.Username, candidates, cutoff, includeNeverUsed)
+		stale = append(stale, userStale...)
+		diags = append(diags, userDiags...)
+	}
+
+	sort.SliceStable(stale, func(i, j int) bool {
+		if stale[i].Username != stale[j].Username {
+			return stale[i].Username < stale[j].Username
+		}
+		if stale[i].Kind != stale[j].Kind {
+			return stale[i].Kind < stale[j].Kind
+		}
+		return stale[i].Label < stale[j].Label
+	})
+
+	// The result depends on the current time, so the ID only identifies the query
+	d.SetId(fmt.Sprintf("stale-credentials:%s", unusedFor))
+
+	if err := d.Set("credentials", flattenStaleCredentials(stale)); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set credentials: %w", err))
+	}
+
+	return diags
+}
+
+// flattenStaleCredentials converts stale credentials to the Terraform schema
+// representation.
+func flattenStaleCredentials(creds []staleCredential) []any {
+	result := make([]any, len(creds))
+	for i, cred := range creds {
+		result[i] = map[string]any{
+			"user_id":            cred.UserID,
+			"username":           cred.Username,
+			"credential_id":      cred.ID,
+			"kind":               string(cred.Kind),
+			"label":              cred.Label,
+			"fingerprint_sha256": cred.Fingerprint,
+			"date_added":         cred.DateAdded,
+			"last_used":          cred.LastUsed,
+			"never_used":         cred.LastUsed == "",
+		}
+	}
+	return result
+}
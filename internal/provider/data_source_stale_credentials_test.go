@@ -0,0 +1,211 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/warpgatetest"
+)
+
+func TestIsStaleCredential(t *testing.T) {
+	cutoff := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		dateAdded        string
+		lastUsed         string
+		includeNeverUsed bool
+		expected         bool
+	}{
+		{"2024-01-01T00:00:00Z", "2024-05-31T23:59:59.123456Z", true, true},
+		{"2024-01-01T00:00:00Z", "2024-06-01T00:00:01Z", true, false},
+		{"2024-01-01T00:00:00Z", "", true, true},
+		{"2024-01-01T00:00:00Z", "", false, false},
+		{"2024-06-02T00:00:00Z", "", true, false},
+	} {
+		got, err := isStaleCredential(tc.dateAdded, tc.lastUsed, cutoff, tc.includeNeverUsed)
+		if err != nil {
+			t.Fatalf("isStaleCredential(%q, %q) returned error: %v", tc.dateAdded, tc.lastUsed, err)
+		}
+		if got != tc.expected {
+			t.Errorf("isStaleCredential(%q, %q, %v) = %v, expected %v", tc.dateAdded, tc.lastUsed, tc.includeNeverUsed, got, tc.expected)
+		}
+	}
+
+	if _, err := isStaleCredential("2024-01-01T00:00:00Z", "yesterday", cutoff, true); err == nil {
+		t.Error("expected an error for an invalid last_used")
+	}
+}
+
+func TestFilterStaleCredentials(t *testing.T) {
+	cutoff := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	stale, diags := filterStaleCredentials("1", "alice", []staleCredential{
+		{ID: "a", Kind: client.CredentialKindPublicKey, Label: "old", DateAdded: "2024-01-01T00:00:00Z"},
+		{ID: "b", Kind: client.CredentialKindPublicKey, Label: "undated"},
+		{ID: "c", Kind: client.CredentialKindCertificate, Label: "new", DateAdded: "2024-06-02T00:00:00Z"},
+	}, cutoff, true)
+
+	if len(stale) != 1 || stale[0].ID != "a" || stale[0].Username != "alice" {
+		t.Errorf("expected only credential a to be stale, got %+v", stale)
+	}
+
+	if len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Fatalf("expected a warning, got %v", diags)
+	}
+	if want := "Skipped PublicKey credential undated of user alice"; diags[0].Summary != want {
+		t.Errorf("expected summary %q, got %q", want, diags[0].Summary)
+	}
+}
+
+// TestStaleCredentialsSkippedKindWarning checks that asking for certificate
+// credentials on a server without them warns instead of silently reporting
+// nothing.
+func TestStaleCredentialsSkippedKindWarning(t *testing.T) {
+	s := warpgatetest.NewServer(testToken)
+	t.Cleanup(s.Close)
+
+	// Older servers don't know the certificate credential endpoints
+	handler := s.Config.Handler
+	s.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/credentials/certificates") {
+			http.NotFound(w, r)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+
+	c, err := client.NewClient(&client.Config{Host: client.AdminAPIURL(s.URL), Token: testToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.CreateUser(context.Background(), &client.UserCreateRequest{Username: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	old := client.Version{Major: 0, Minor: 16, Patch: 2}
+	meta := &providerMeta{client: c, serverVersion: &old}
+
+	for _, tc := range []struct {
+		kinds    []any
+		warnings int
+	}{
+		{nil, 0},
+		{[]any{"PublicKey"}, 0},
+		{[]any{"PublicKey", "Certificate"}, 1},
+	} {
+		d := dataSourceStaleCredentials().TestResourceData()
+		if err := d.Set("unused_for", "1h"); err != nil {
+			t.Fatal(err)
+		}
+		if err := d.Set("credential_kinds", tc.kinds); err != nil {
+			t.Fatal(err)
+		}
+
+		diags := dataSourceStaleCredentialsRead(context.Background(), d, meta)
+		if diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		if len(diags) != tc.warnings {
+			t.Errorf("credential_kinds %v: expected %d warnings, got %v", tc.kinds, tc.warnings, diags)
+		}
+	}
+
+	// On a server expected to support them, the failure is an error
+	current := client.Version{Major: 0, Minor: 17}
+	d := dataSourceStaleCredentials().TestResourceData()
+	if err := d.Set("unused_for", "1h"); err != nil {
+		t.Fatal(err)
+	}
+	if diags := dataSourceStaleCredentialsRead(context.Background(), d, &providerMeta{client: c, serverVersion: &current}); !diags.HasError() {
+		t.Errorf("expected an error, got %v", diags)
+	}
+}
+
+func TestAccStaleCredentialsDataSource(t *testing.T) {
+	username := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStaleCredentialsConfig(username, "2160h", true),
+				Check:  testAccCheckStaleCredentialCount(username, 0),
+			},
+			{
+				// The key was never used, and is stale once it is older than unused_for
+				PreConfig: func() { time.Sleep(2 * time.Second) },
+				Config:    testAccStaleCredentialsConfig(username, "1s", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStaleCredentialCount(username, 1),
+					resource.TestCheckTypeSetElemNestedAttrs("data.warpgate_stale_credentials.test", "credentials.*", map[string]string{
+						"username":           username,
+						"kind":               "PublicKey",
+						"label":              "laptop",
+						"fingerprint_sha256": "SHA256:NiXkhsnNpAf8VwIz0LPT0Z2rZzppGMHKFJdO9csKT3g",
+						"last_used":          "",
+						"never_used":         "true",
+					}),
+				),
+			},
+			{
+				Config: testAccStaleCredentialsConfig(username, "1s", false),
+				Check:  testAccCheckStaleCredentialCount(username, 0),
+			},
+		},
+	})
+}
+
+func testAccStaleCredentialsConfig(username, unusedFor string, includeNeverUsed bool) string {
+	return fmt.Sprintf(`
+resource "warpgate_user" "test" {
+  username = %q
+}
+
+resource "warpgate_public_key_credential" "test" {
+  user_id    = warpgate_user.test.id
+  label      = "laptop"
+  public_key = %q
+}
+
+data "warpgate_stale_credentials" "test" {
+  unused_for         = %q
+  include_never_used = %t
+  credential_kinds   = ["PublicKey"]
+
+  depends_on = [warpgate_public_key_credential.test]
+}
+`, username, testAccPublicKey, unusedFor, includeNeverUsed)
+}
+
+// testAccCheckStaleCredentialCount returns a check verifying how many stale
+// credentials the data source reports for the user.
+func testAccCheckStaleCredentialCount(username string, count int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["data.warpgate_stale_credentials.test"]
+		if !ok {
+			return fmt.Errorf("data.warpgate_stale_credentials.test not found in state")
+		}
+
+		found := 0
+		for key, value := range rs.Primary.Attributes {
+			if strings.HasPrefix(key, "credentials.") && strings.HasSuffix(key, ".username") && value == username {
+				found++
+			}
+		}
+
+		if found != count {
+			return fmt.Errorf("expected %d stale credentials of %s, got %d", count, username, found)
+		}
+
+		return nil
+	}
+}
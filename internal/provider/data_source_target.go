@@ -4,6 +4,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -284,25 +285,23 @@ func dataSourceTarget() *schema.Resource {
 								},
 							},
 						},
+						"auth_kind": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "How Warpgate authenticates to the cluster (Token, Certificate)",
+						},
 						"token_auth": {
 							Type:        schema.TypeList,
 							Computed:    true,
-							Description: "Token authentication for Kubernetes",
+							Description: "Token authentication for Kubernetes. The token itself is not exposed.",
 							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"token": {
-										Type:        schema.TypeString,
-										Computed:    true,
-										Sensitive:   true,
-										Description: "The bearer token for Kubernetes authentication",
-									},
-								},
+								Schema: map[string]*schema.Schema{},
 							},
 						},
 						"certificate_auth": {
 							Type:        schema.TypeList,
 							Computed:    true,
-							Description: "Certificate authentication for Kubernetes",
+							Description: "Certificate authentication for Kubernetes. The private key is not exposed.",
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
 									"certificate": {
@@ -310,18 +309,27 @@ func dataSourceTarget() *schema.Resource {
 										Computed:    true,
 										Description: "The client certificate PEM",
 									},
-									"private_key": {
-										Type:        schema.TypeString,
-										Computed:    true,
-										Sensitive:   true,
-										Description: "The client private key PEM",
-									},
 								},
 							},
 						},
 					},
 				},
 			},
+			"kubeconfig_host": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The Warpgate host that Kubernetes clients connect to, optionally with a port. Defaults to the external host reported by the server. Without a port, the Kubernetes port reported by the server is used.",
+			},
+			"kubeconfig_username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The Warpgate user to render `kubeconfig` for",
+			},
+			"kubeconfig": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "A kubeconfig that connects to this Kubernetes target through Warpgate as `kubeconfig_username`. Only set for Kubernetes targets when `kubeconfig_username` is set. It contains no credentials.",
+			},
 		},
 	}
 }
@@ -360,7 +368,9 @@ func dataSourceTargetRead(ctx context.Context, d *schema.ResourceData, meta any)
 		}
 	} else {
 		idStr := id.(string)
-		target, err := c.GetTarget(ctx, idStr)
+
+		var err error
+		target, err = c.GetTarget(ctx, idStr)
 		if err != nil {
 			return diag.FromErr(fmt.Errorf("failed to read target: %w", err))
 		}
@@ -388,9 +398,98 @@ func dataSourceTargetRead(ctx context.Context, d *schema.ResourceData, meta any)
 	}
 
 	// Set the appropriate options block based on target type
-	if err := setTargetOptions(d, target.Options); err != nil {
+	optionsMap, err := targetOptionsToMap(target.Options)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to convert target options to map: %w", err))
+	}
+
+	if kind, _ := optionsMap["kind"].(string); kind != "Kubernetes" {
+		if err := setTargetOptions(d, target.Options); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to set target options: %w", err))
+		}
+		return diags
+	}
+
+	k8sOpts, err := flattenKubernetesTargetDataSourceOptions(optionsMap)
+	if err != nil {
 		return diag.FromErr(fmt.Errorf("failed to set target options: %w", err))
 	}
 
+	if err := d.Set("kubernetes_options", []any{k8sOpts}); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set kubernetes_options: %w", err))
+	}
+
+	kubeconfig := ""
+	if username := d.Get("kubeconfig_username").(string); username != "" {
+		host := d.Get("kubeconfig_host").(string)
+		port := 0
+		if info := providerMeta.serverInfo; info != nil {
+			if host == "" && info.ExternalHost != nil {
+				// The external host may carry the port of the HTTP listener
+				host = *info.ExternalHost
+				if hostname, _, err := net.SplitHostPort(host); err == nil {
+					host = hostname
+				}
+			}
+			if info.Ports.Kubernetes != nil {
+				port = *info.Ports.Kubernetes
+			}
+		}
+		if host == "" {
+			return diag.Errorf("kubeconfig_host must be set as the server does not report its external host")
+		}
+
+		kubeconfig, err = renderKubeconfig(kubernetesProxyURL(host, port, target.Name), target.Name, username)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if err := d.Set("kubeconfig", kubeconfig); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set kubeconfig: %w", err))
+	}
+
 	return diags
 }
+
+// flattenKubernetesTargetDataSourceOptions converts the options of a
+// Kubernetes target to the kubernetes_options block of the data source, which
+// reports the authentication kind but leaves out the token and private key.
+func flattenKubernetesTargetDataSourceOptions(optionsMap map[string]any) (map[string]any, error) {
+	tls, ok := optionsMap["tls"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid tls field in Kubernetes options")
+	}
+
+	auth, ok := optionsMap["auth"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid auth field in Kubernetes options")
+	}
+
+	authKind, ok := auth["kind"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing 'kind' field in Kubernetes auth options")
+	}
+
+	k8sOpts := map[string]any{
+		"cluster_url": optionsMap["cluster_url"],
+		"tls": []any{map[string]any{
+			"mode":   tls["mode"],
+			"verify": tls["verify"],
+		}},
+		"auth_kind": authKind,
+	}
+
+	switch authKind {
+	case "Token":
+		k8sOpts["token_auth"] = []any{map[string]any{}}
+	case "Certificate":
+		k8sOpts["certificate_auth"] = []any{map[string]any{
+			"certificate": auth["certificate"],
+		}}
+	default:
+		return nil, fmt.Errorf("unknown Kubernetes auth kind: %s", authKind)
+	}
+
+	return k8sOpts, nil
+}
@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceTarget(t *testing.T) {
+	name := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "warpgate_role" "test" {
+  name = %[1]q
+}
+
+resource "warpgate_target" "test" {
+  name        = %[1]q
+  description = "Test target"
+
+  ssh_options {
+    host     = "10.0.0.1"
+    port     = 22
+    username = "root"
+
+    public_key_auth {}
+  }
+}
+
+resource "warpgate_target_role" "test" {
+  target_id = warpgate_target.test.id
+  role_id   = warpgate_role.test.id
+}
+
+data "warpgate_target" "by_id" {
+  id = warpgate_target.test.id
+
+  depends_on = [warpgate_target_role.test]
+}
+
+data "warpgate_target" "by_name" {
+  name = warpgate_target.test.name
+
+  depends_on = [warpgate_target_role.test]
+}
+`, name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.warpgate_target.by_id", "name", name),
+					resource.TestCheckResourceAttr("data.warpgate_target.by_id", "description", "Test target"),
+					resource.TestCheckResourceAttr("data.warpgate_target.by_id", "ssh_options.0.host", "10.0.0.1"),
+					resource.TestCheckResourceAttr("data.warpgate_target.by_id", "allow_roles.#", "1"),
+					resource.TestCheckResourceAttr("data.warpgate_target.by_id", "allow_roles.0", name),
+					resource.TestCheckResourceAttrPair("data.warpgate_target.by_name", "id", "warpgate_target.test", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceTarget_kubernetes(t *testing.T) {
+	name := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "warpgate_target" "test" {
+  name = %[1]q
+
+  kubernetes_options {
+    cluster_url = "https://k8s.example.com:6443"
+
+    tls {
+      mode   = "Required"
+      verify = true
+    }
+
+    token_auth {
+      token = "secret-token"
+    }
+  }
+}
+
+data "warpgate_target" "test" {
+  id                  = warpgate_target.test.id
+  kubeconfig_host     = "warpgate.example.com"
+  kubeconfig_username = "alice"
+}
+`, name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.warpgate_target.test", "kubernetes_options.0.cluster_url", "https://k8s.example.com:6443"),
+					resource.TestCheckResourceAttr("data.warpgate_target.test", "kubernetes_options.0.tls.0.mode", "Required"),
+					resource.TestCheckResourceAttr("data.warpgate_target.test", "kubernetes_options.0.auth_kind", "Token"),
+					resource.TestCheckResourceAttr("data.warpgate_target.test", "kubernetes_options.0.token_auth.#", "1"),
+					resource.TestCheckNoResourceAttr("data.warpgate_target.test", "kubernetes_options.0.token_auth.0.token"),
+					resource.TestCheckResourceAttr("data.warpgate_target.test", "kubernetes_options.0.certificate_auth.#", "0"),
+					resource.TestMatchResourceAttr("data.warpgate_target.test", "kubeconfig", regexp.MustCompile(`server: https://warpgate\.example\.com:8443/`+name+`\n`)),
+					resource.TestMatchResourceAttr("data.warpgate_target.test", "kubeconfig", regexp.MustCompile(`current-context: alice@`+name+`\n`)),
+				),
+			},
+		},
+	})
+}
@@ -35,43 +35,11 @@ func dataSourceUser() *schema.Resource {
 				Computed:    true,
 				Description: "The description of the user",
 			},
-			"credential_policy": {
-				Type:        schema.TypeList,
-				Computed:    true,
-				Description: "The credential policy for the user",
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"http": {
-							Type:     schema.TypeList,
-							Computed: true,
-							Elem: &schema.Schema{
-								Type: schema.TypeString,
-							},
-						},
-						"ssh": {
-							Type:     schema.TypeList,
-							Computed: true,
-							Elem: &schema.Schema{
-								Type: schema.TypeString,
-							},
-						},
-						"mysql": {
-							Type:     schema.TypeList,
-							Computed: true,
-							Elem: &schema.Schema{
-								Type: schema.TypeString,
-							},
-						},
-						"postgres": {
-							Type:     schema.TypeList,
-							Computed: true,
-							Elem: &schema.Schema{
-								Type: schema.TypeString,
-							},
-						},
-					},
-				},
-			},
+			"credential_policy": computedCredentialPolicySchema("The credential policy for the user"),
+			"unsatisfied_credential_policy": computedCredentialPolicySchema(
+				"The credential kinds of the credential policy that the user has no credentials of, per protocol. " +
+					"The user can't log in over protocols listed here. Empty if every protocol can be logged in with.",
+			),
 			"sso_credentials": {
 				Type:        schema.TypeList,
 				Computed:    true,
@@ -108,6 +76,44 @@ func dataSourceUser() *schema.Resource {
 	}
 }
 
+// computedCredentialPolicySchema returns the schema of a computed block listing
+// credential kinds per protocol, like the credential_policy of a user.
+func computedCredentialPolicySchema(description string) *schema.Schema {
+	protocols := make(map[string]*schema.Schema, len(credentialPolicyProtocols))
+	for key := range credentialPolicyProtocols {
+		protocols[key] = &schema.Schema{
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		}
+	}
+
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: description,
+		Elem: &schema.Resource{
+			Schema: protocols,
+		},
+	}
+}
+
+// flattenUnsatisfiedCredentialPolicy converts the result of
+// unsatisfiedCredentialPolicy to the Terraform schema representation.
+func flattenUnsatisfiedCredentialPolicy(unsatisfied map[string][]client.CredentialKind) []any {
+	if len(unsatisfied) == 0 {
+		return nil
+	}
+
+	result := make(map[string]any, len(unsatisfied))
+	for key, kinds := range unsatisfied {
+		result[key] = flattenCredentialKindList(kinds)
+	}
+	return []any{result}
+}
+
 // flattenSsoCredentials converts a slice of SSO credentials from the Warpgate API format
 // to the Terraform schema representation.
 func flattenSsoCredentials(credentials []client.SsoCredential) []any {
@@ -189,6 +195,16 @@ func dataSourceUserRead(ctx context.Context, d *schema.ResourceData, meta any) d
 		}
 	}
 
+	// Without the credentials, unsatisfied_credential_policy is left empty
+	unsatisfied, err := unsatisfiedCredentialPolicy(ctx, c, user.ID, user.CredentialPolicy)
+	if err != nil {
+		diags = append(diags, credentialPolicyCheckWarning(user.Username, err))
+	}
+
+	if err := d.Set("unsatisfied_credential_policy", flattenUnsatisfiedCredentialPolicy(unsatisfied)); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set unsatisfied_credential_policy: %w", err))
+	}
+
 	if user.AllowedIPRanges != nil {
 		if err := d.Set("allowed_ip_ranges", *user.AllowedIPRanges); err != nil {
 			return diag.FromErr(fmt.Errorf("failed to set allowed_ip_ranges: %w", err))
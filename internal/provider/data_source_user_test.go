@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/warpgatetest"
+)
+
+func TestAccDataSourceUser(t *testing.T) {
+	username := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "warpgate_user" "test" {
+  username    = %q
+  description = "Test user"
+
+  credential_policy {
+    http       = ["Password", "Totp"]
+    kubernetes = ["Certificate"]
+  }
+}
+
+resource "warpgate_user_sso_credential" "test" {
+  user_id      = warpgate_user.test.id
+  sso_provider = "google"
+  email        = "alice@example.com"
+}
+
+resource "warpgate_password_credential" "test" {
+  user_id  = warpgate_user.test.id
+  password = "correct horse battery staple"
+}
+
+data "warpgate_user" "by_id" {
+  id = warpgate_user.test.id
+
+  depends_on = [warpgate_user_sso_credential.test, warpgate_password_credential.test]
+}
+
+data "warpgate_user" "by_username" {
+  username = warpgate_user.test.username
+
+  depends_on = [warpgate_user_sso_credential.test, warpgate_password_credential.test]
+}
+`, username),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.warpgate_user.by_id", "username", username),
+					resource.TestCheckResourceAttr("data.warpgate_user.by_id", "description", "Test user"),
+					resource.TestCheckResourceAttr("data.warpgate_user.by_id", "credential_policy.0.http.#", "2"),
+					resource.TestCheckResourceAttr("data.warpgate_user.by_id", "credential_policy.0.kubernetes.0", "Certificate"),
+					resource.TestCheckResourceAttr("data.warpgate_user.by_id", "unsatisfied_credential_policy.0.http.#", "1"),
+					resource.TestCheckResourceAttr("data.warpgate_user.by_id", "unsatisfied_credential_policy.0.http.0", "Totp"),
+					resource.TestCheckResourceAttr("data.warpgate_user.by_id", "unsatisfied_credential_policy.0.kubernetes.0", "Certificate"),
+					resource.TestCheckResourceAttr("data.warpgate_user.by_id", "unsatisfied_credential_policy.0.ssh.#", "0"),
+					resource.TestCheckResourceAttr("data.warpgate_user.by_id", "sso_credentials.#", "1"),
+					resource.TestCheckResourceAttr("data.warpgate_user.by_id", "sso_credentials.0.email", "alice@example.com"),
+					resource.TestCheckResourceAttrPair("data.warpgate_user.by_username", "id", "warpgate_user.test", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceUserCredentialPolicyCheckFailure(t *testing.T) {
+	s := warpgatetest.NewServer(testToken)
+	t.Cleanup(s.Close)
+
+	// Looking up the credentials the policy requires fails
+	handler := s.Config.Handler
+	s.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/credentials/passwords") {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+
+	c, err := client.NewClient(&client.Config{Host: client.AdminAPIURL(s.URL), Token: testToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := c.CreateUser(context.Background(), &client.UserCreateRequest{Username: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.UpdateUser(context.Background(), user.ID, &client.UserUpdateRequest{
+		Username:         user.Username,
+		CredentialPolicy: &client.UserRequireCredentialsPolicy{HTTP: []client.CredentialKind{client.CredentialKindPassword}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	d := dataSourceUser().TestResourceData()
+	if err := d.Set("id", user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	diags := dataSourceUserRead(context.Background(), d, &providerMeta{client: c})
+	if len(diags) != 1 || diags[0].Severity != diag.Warning || diags[0].Summary != "Failed to check the credential policy of user alice" {
+		t.Fatalf("expected a single warning about the credential policy check, got %v", diags)
+	}
+
+	if d.Get("username").(string) != "alice" {
+		t.Errorf("expected the user to be read, got username %q", d.Get("username"))
+	}
+	if n := len(d.Get("unsatisfied_credential_policy").([]any)); n != 0 {
+		t.Errorf("expected no unsatisfied_credential_policy, got %d entries", n)
+	}
+}
@@ -0,0 +1,149 @@
+// Package provider implements the Terraform provider for Warpgate
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+)
+
+// ticketIDPrivateKey is the private data key under which Open passes the
+// ticket ID to Close.
+const ticketIDPrivateKey = "ticket_id"
+
+// ticketEphemeralResource issues a ticket that only lives for the duration of
+// a Terraform run, so that its secret never ends up in the state.
+type ticketEphemeralResource struct {
+	meta *providerMeta
+}
+
+// ticketEphemeralResourceModel maps the ephemeral ticket schema.
+type ticketEphemeralResourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	Username     types.String `tfsdk:"username"`
+	TargetName   types.String `tfsdk:"target_name"`
+	Expiry       types.String `tfsdk:"expiry"`
+	NumberOfUses types.Int64  `tfsdk:"number_of_uses"`
+	Description  types.String `tfsdk:"description"`
+	Secret       types.String `tfsdk:"secret"`
+}
+
+var (
+	_ ephemeral.EphemeralResourceWithConfigure = &ticketEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose     = &ticketEphemeralResource{}
+)
+
+// newTicketEphemeralResource creates the ephemeral ticket resource.
+func newTicketEphemeralResource() ephemeral.EphemeralResource {
+	return &ticketEphemeralResource{}
+}
+
+// Metadata returns the ephemeral ticket resource type name.
+func (r *ticketEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ticket"
+}
+
+// Schema returns the schema for the ephemeral ticket resource, which takes the
+// same arguments as the warpgate_ticket resource.
+func (r *ticketEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Issues a Warpgate ticket that is deleted again at the end of the Terraform run. The ticket secret is only available during the run and is never stored in the state.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the ticket",
+			},
+			"username": schema.StringAttribute{
+				Required:    true,
+				Description: "The user associated with the ticket. Will determine the permissions and access rights.",
+			},
+			"target_name": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the target the ticket grants access to.",
+			},
+			"expiry": schema.StringAttribute{
+				Optional:    true,
+				Description: "The expiry time of the ticket. The ticket is deleted at the end of the run even if it has not expired.",
+			},
+			"number_of_uses": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The number of uses allowed for the ticket before it becomes invalid.",
+			},
+			"description": schema.StringAttribute{
+				Optional:    true,
+				Description: "The description of the ticket.",
+			},
+			"secret": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The secret value of the ticket used for authentication.",
+			},
+		},
+	}
+}
+
+// Configure stores the provider metadata for use in Open and Close.
+func (r *ticketEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	r.meta = providerMetaFromData(req.ProviderData, &resp.Diagnostics)
+}
+
+// Open creates the ticket and returns its secret.
+func (r *ticketEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	c := r.meta.client
+
+	var config ticketEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ticket, err := c.CreateTicket(ctx, &client.TicketCreateRequest{
+		Username:     config.Username.ValueString(),
+		TargetName:   config.TargetName.ValueString(),
+		Expiry:       config.Expiry.ValueString(),
+		NumberOfUses: int(config.NumberOfUses.ValueInt64()),
+		Description:  config.Description.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating ticket", fmt.Sprintf("failed to create ticket: %s", err))
+		return
+	}
+
+	privateID, err := json.Marshal(ticket.Ticket.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating ticket", fmt.Sprintf("failed to encode ticket ID: %s", err))
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, ticketIDPrivateKey, privateID)...)
+
+	config.ID = types.StringValue(ticket.Ticket.ID)
+	config.Secret = types.StringValue(ticket.Secret)
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, config)...)
+}
+
+// Close deletes the ticket created by Open.
+func (r *ticketEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	c := r.meta.client
+
+	privateID, diags := req.Private.GetKey(ctx, ticketIDPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || privateID == nil {
+		return
+	}
+
+	var id string
+	if err := json.Unmarshal(privateID, &id); err != nil {
+		resp.Diagnostics.AddError("Error deleting ticket", fmt.Sprintf("failed to decode ticket ID: %s", err))
+		return
+	}
+
+	if err := c.DeleteTicket(ctx, id); err != nil {
+		resp.Diagnostics.AddError("Error deleting ticket", fmt.Sprintf("failed to delete ticket: %s", err))
+	}
+}
@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccEphemeralTicket(t *testing.T) {
+	name := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEphemeralTicketConfig(name),
+				Check:  testAccCheckNoTicket(name),
+			},
+		},
+	})
+}
+
+func testAccEphemeralTicketConfig(name string) string {
+	return fmt.Sprintf(`
+resource "warpgate_user" "test" {
+  username = %[1]q
+}
+
+resource "warpgate_target" "test" {
+  name = %[1]q
+
+  http_options {
+    url = "https://internal.example.com"
+
+    tls {
+      mode   = "Disabled"
+      verify = false
+    }
+  }
+}
+
+ephemeral "warpgate_ticket" "test" {
+  username       = warpgate_user.test.username
+  target_name    = warpgate_target.test.name
+  number_of_uses = 1
+  description    = %[1]q
+}
+`, name)
+}
+
+// testAccCheckNoTicket verifies that the ticket with the given description has
+// been deleted again when the run ended.
+func testAccCheckNoTicket(description string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		c, err := testAccClient()
+		if err != nil {
+			return err
+		}
+
+		tickets, err := c.GetTickets(context.Background())
+		if err != nil {
+			return err
+		}
+
+		for _, ticket := range tickets {
+			if ticket.Description == description {
+				return fmt.Errorf("ticket %s still exists", ticket.ID)
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,72 @@
+// Package provider implements the Terraform provider for Warpgate
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+)
+
+// serverFeature is a piece of configuration that is expected to only work with
+// Warpgate releases starting from minVersion.
+type serverFeature struct {
+	name       string
+	minVersion client.Version
+}
+
+// Version-dependent features, with the first Warpgate release expected to
+// support them. None of these versions has been confirmed against the Warpgate
+// release notes, so an older server only causes a warning and the server has
+// the final say.
+var (
+	featureRecordSCP               = serverFeature{name: "record_scp", minVersion: client.Version{Major: 0, Minor: 15}}
+	featurePostgresProtocolVersion = serverFeature{name: "postgres_options.protocol_version", minVersion: client.Version{Major: 0, Minor: 16}}
+	featureKubernetesTargets       = serverFeature{name: "kubernetes_options", minVersion: client.Version{Major: 0, Minor: 17}}
+	featureKubernetesPolicy        = serverFeature{name: "credential_policy.kubernetes", minVersion: client.Version{Major: 0, Minor: 17}}
+	featureCertificateCredentials  = serverFeature{name: "warpgate_certificate_credential", minVersion: client.Version{Major: 0, Minor: 17}}
+)
+
+// checkServerFeature returns an error if the server is known to be older than
+// the first release expected to support the feature. If the server version
+// could not be determined, every feature is allowed.
+func checkServerFeature(meta any, feature serverFeature) error {
+	providerMeta, ok := meta.(*providerMeta)
+	if !ok || providerMeta.serverVersion == nil {
+		return nil
+	}
+
+	if providerMeta.serverVersion.AtLeast(feature.minVersion) {
+		return nil
+	}
+
+	return fmt.Errorf("%s is expected to require Warpgate >= %s, but the server runs %s", feature.name, feature.minVersion, providerMeta.serverVersion)
+}
+
+// serverFeatureWarnings returns a warning if the server is older than the first
+// release expected to support the feature. The configuration is sent anyway,
+// and the warning explains the error if the server rejects it.
+func serverFeatureWarnings(meta any, feature serverFeature, path cty.Path) diag.Diagnostics {
+	err := checkServerFeature(meta, feature)
+	if err == nil {
+		return nil
+	}
+
+	return diag.Diagnostics{{
+		Severity:      diag.Warning,
+		Summary:       "Feature possibly unsupported by the Warpgate server",
+		Detail:        fmt.Sprintf("%s. If Warpgate rejects the request, upgrade the server or remove %s.", err, feature.name),
+		AttributePath: path,
+	}}
+}
+
+// withFeatureWarnings wraps a create or update function, adding the warnings
+// returned by warnings for the features the configuration uses.
+func withFeatureWarnings(fn func(context.Context, *schema.ResourceData, any) diag.Diagnostics, warnings func(d *schema.ResourceData, meta any) diag.Diagnostics) func(context.Context, *schema.ResourceData, any) diag.Diagnostics {
+	return func(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+		return append(warnings(d, meta), fn(ctx, d, meta)...)
+	}
+}
@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+)
+
+func TestCheckServerFeature(t *testing.T) {
+	old := client.Version{Major: 0, Minor: 16, Patch: 2}
+	current := client.Version{Major: 0, Minor: 17, Patch: 0}
+
+	if err := checkServerFeature(nil, featureKubernetesTargets); err != nil {
+		t.Fatalf("expected no error without provider meta, got %v", err)
+	}
+
+	if err := checkServerFeature(&providerMeta{}, featureKubernetesTargets); err != nil {
+		t.Fatalf("expected no error for unknown server version, got %v", err)
+	}
+
+	if err := checkServerFeature(&providerMeta{serverVersion: &current}, featureKubernetesTargets); err != nil {
+		t.Fatalf("expected no error for supported server version, got %v", err)
+	}
+
+	err := checkServerFeature(&providerMeta{serverVersion: &old}, featureKubernetesTargets)
+	if err == nil {
+		t.Fatalf("expected error for old server version, got nil")
+	}
+	if want := "kubernetes_options is expected to require Warpgate >= 0.17.0, but the server runs 0.16.2"; err.Error() != want {
+		t.Fatalf("expected %q, got %q", want, err.Error())
+	}
+}
+
+// TestServerFeatureWarnedAtApply checks that configuration the server may be
+// too old for is still applied, with a warning, since the minimum versions are
+// not confirmed.
+func TestServerFeatureWarnedAtApply(t *testing.T) {
+	s := testAccStartServer(t)
+	s.Version = "v0.16.2"
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccServerFeatureTargetConfig,
+				Check:  resource.TestCheckResourceAttrSet("warpgate_target.test", "id"),
+			},
+		},
+	})
+
+	// The warning is kept when the server rejects the request, to explain why
+	old := client.Version{Major: 0, Minor: 16, Patch: 2}
+	d := resourceTarget().TestResourceData()
+	if err := d.Set("kubernetes_options", []any{map[string]any{
+		"cluster_url": "https://k8s.example.com:6443",
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	create := withFeatureWarnings(func(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+		return diag.Errorf("bad request")
+	}, targetFeatureWarnings)
+
+	diags := create(context.Background(), d, &providerMeta{serverVersion: &old})
+	if len(diags) != 2 || diags[0].Severity != diag.Warning || !strings.Contains(diags[0].Detail, "kubernetes_options is expected to require Warpgate >= 0.17.0") || diags[1].Summary != "bad request" {
+		t.Errorf("expected a warning about kubernetes_options and the error, got %v", diags)
+	}
+}
+
+const testAccServerFeatureTargetConfig = `
+resource "warpgate_target" "test" {
+  name = "k8s"
+
+  kubernetes_options {
+    cluster_url = "https://k8s.example.com:6443"
+
+    tls {
+      mode   = "Required"
+      verify = true
+    }
+
+    token_auth {
+      token = "secret"
+    }
+  }
+}
+`
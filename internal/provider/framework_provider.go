@@ -0,0 +1,170 @@
+// Package provider implements the Terraform provider for Warpgate
+package provider
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	fwprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// frameworkProvider is the part of the Warpgate provider built on
+// terraform-plugin-framework. It is served together with the SDK provider, so
+// its schema must stay identical to the SDK provider schema.
+type frameworkProvider struct {
+	version string
+
+	// metas is shared with the SDK provider when both are served together
+	metas *providerMetaCache
+}
+
+// frameworkProviderModel maps the provider configuration.
+type frameworkProviderModel struct {
+	Host               types.String `tfsdk:"host"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+	Token              types.String `tfsdk:"token"`
+}
+
+var (
+	_ fwprovider.ProviderWithEphemeralResources = &frameworkProvider{}
+	_ fwprovider.ProviderWithFunctions          = &frameworkProvider{}
+)
+
+// NewFramework returns a function that creates the framework-based part of the
+// Warpgate provider with the specified version information.
+func NewFramework(version string) func() fwprovider.Provider {
+	return func() fwprovider.Provider {
+		return &frameworkProvider{
+			version: version,
+			metas:   &providerMetaCache{},
+		}
+	}
+}
+
+// Metadata returns the provider type name and version.
+func (p *frameworkProvider) Metadata(ctx context.Context, req fwprovider.MetadataRequest, resp *fwprovider.MetadataResponse) {
+	resp.TypeName = "warpgate"
+	resp.Version = p.version
+}
+
+// Schema returns the provider configuration schema, which mirrors the one in New.
+func (p *frameworkProvider) Schema(ctx context.Context, req fwprovider.SchemaRequest, resp *fwprovider.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"host": schema.StringAttribute{
+				Optional:    true,
+				Description: "The Warpgate API host URL (e.g., https://warpgate.example.com)",
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to skip the TLS certificate verification (self-signed certificates)",
+			},
+			"token": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "API token for authenticating with Warpgate API",
+			},
+		},
+	}
+}
+
+// Configure creates the Warpgate client, falling back to the same environment
+// variables as the SDK provider for settings missing from the configuration.
+func (p *frameworkProvider) Configure(ctx context.Context, req fwprovider.ConfigureRequest, resp *fwprovider.ConfigureResponse) {
+	var config frameworkProviderModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	host := stringValueOrEnv(config.Host, "WARPGATE_HOST")
+	token := stringValueOrEnv(config.Token, "WARPGATE_TOKEN")
+
+	insecureSkipVerify := config.InsecureSkipVerify.ValueBool()
+	if config.InsecureSkipVerify.IsNull() {
+		if v := os.Getenv("WARPGATE_INSECURE_SKIP_VERIFY"); v != "" {
+			parsed, err := strconv.ParseBool(v)
+			if err != nil {
+				resp.Diagnostics.AddError("Invalid WARPGATE_INSECURE_SKIP_VERIFY", err.Error())
+				return
+			}
+			insecureSkipVerify = parsed
+		}
+	}
+
+	// The SDK provider is configured with the same settings and already
+	// reports both errors and serverInfoErr, so they are not repeated here.
+	// Terraform stops before using any resource if configuration fails.
+	meta, err := p.metas.get(ctx, host, token, insecureSkipVerify)
+	if err != nil {
+		return
+	}
+
+	resp.DataSourceData = meta
+	resp.ResourceData = meta
+	resp.EphemeralResourceData = meta
+}
+
+// Resources returns the resources that have been migrated to the framework.
+// A resource must be removed from the SDK provider when it is added here.
+func (p *frameworkProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		newRoleResource,
+	}
+}
+
+// DataSources returns the data sources that have been migrated to the framework.
+func (p *frameworkProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+// EphemeralResources returns the ephemeral resources, which are only
+// supported by the framework.
+func (p *frameworkProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		newTicketEphemeralResource,
+	}
+}
+
+// Functions returns the provider-defined functions, which are only supported
+// by the framework.
+func (p *frameworkProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		newPublicKeyToOpenSSHFunction,
+	}
+}
+
+// stringValueOrEnv returns the configured value, or the value of the
+// environment variable if it is not configured.
+func stringValueOrEnv(value types.String, env string) string {
+	if value.IsNull() {
+		return os.Getenv(env)
+	}
+	return value.ValueString()
+}
+
+// providerMetaFromData converts the provider data passed to framework resources
+// and data sources. The data is nil before the provider is configured, which
+// is not an error.
+func providerMetaFromData(data any, diags *diag.Diagnostics) *providerMeta {
+	if data == nil {
+		return nil
+	}
+
+	meta, ok := data.(*providerMeta)
+	if !ok {
+		diags.AddError("Unexpected provider data", "The provider data has an unexpected type. Please report this issue to the provider developers.")
+		return nil
+	}
+
+	return meta
+}
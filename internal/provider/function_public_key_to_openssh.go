@@ -0,0 +1,56 @@
+// Package provider implements the Terraform provider for Warpgate
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// publicKeyToOpenSSHFunction converts a public key to the OpenSSH
+// authorized_keys format Warpgate expects.
+type publicKeyToOpenSSHFunction struct{}
+
+var _ function.Function = &publicKeyToOpenSSHFunction{}
+
+// newPublicKeyToOpenSSHFunction creates the public_key_to_openssh function.
+func newPublicKeyToOpenSSHFunction() function.Function {
+	return &publicKeyToOpenSSHFunction{}
+}
+
+// Metadata returns the function name.
+func (f *publicKeyToOpenSSHFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "public_key_to_openssh"
+}
+
+// Definition returns the function signature.
+func (f *publicKeyToOpenSSHFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Convert a public key to the OpenSSH format",
+		Description: "Converts a public key in OpenSSH authorized_keys, RFC 4716, PEM (SubjectPublicKeyInfo or PKCS #1) or JWK format to the OpenSSH authorized_keys format, without a comment.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "public_key",
+				Description: "The public key to convert",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run converts the public key.
+func (f *publicKeyToOpenSSHFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var publicKey string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &publicKey))
+	if resp.Error != nil {
+		return
+	}
+
+	if _, err := parsePublicKey(publicKey); err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "the key "+err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, normalizePublicKey(publicKey)))
+}
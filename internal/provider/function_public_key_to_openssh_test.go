@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"golang.org/x/crypto/ssh"
+)
+
+// testAccRequiredProviders declares the provider, which Terraform requires for
+// calling provider-defined functions. The test framework serves the provider
+// under the default hashicorp namespace.
+const testAccRequiredProviders = `
+terraform {
+  required_providers {
+    warpgate = {
+      source = "hashicorp/warpgate"
+    }
+  }
+}
+`
+
+func TestAccPublicKeyToOpenSSHFunction(t *testing.T) {
+	key, err := parsePublicKey(testAccPublicKey)
+	if err != nil {
+		t.Fatalf("failed to parse key: %v", err)
+	}
+	pemKey := testPublicKeyFormats(t, key.(ssh.CryptoPublicKey).CryptoPublicKey())[publicKeyFormatPEM]
+
+	testAccTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRequiredProviders + fmt.Sprintf(`
+output "openssh" {
+  value = provider::warpgate::public_key_to_openssh(%q)
+}
+`, pemKey),
+				Check: resource.TestCheckOutput("openssh", testAccPublicKey),
+			},
+			{
+				Config: testAccRequiredProviders + `
+output "openssh" {
+  value = provider::warpgate::public_key_to_openssh("not a key")
+}
+`,
+				ExpectError: regexp.MustCompile(`the key is not a valid OpenSSH\s+public key`),
+			},
+		},
+	})
+}
@@ -0,0 +1,201 @@
+// Package provider implements the Terraform provider for Warpgate
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+)
+
+// importByNamePrefix is the prefix of import IDs that refer to an object by its
+// human-readable name instead of its UUID.
+const importByNamePrefix = "name="
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// isUUID reports whether the given string looks like a Warpgate object ID.
+func isUUID(s string) bool {
+	return uuidPattern.MatchString(s)
+}
+
+// importStateByName returns an importer that accepts either the object ID or
+// "name=<name>". Names are resolved to IDs using the provided lookup function.
+func importStateByName(lookup func(ctx context.Context, c *client.Client, name string) (string, error)) schema.StateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+		providerMeta := meta.(*providerMeta)
+		c := providerMeta.client
+
+		name, ok := strings.CutPrefix(d.Id(), importByNamePrefix)
+		if !ok {
+			return []*schema.ResourceData{d}, nil
+		}
+
+		id, err := lookup(ctx, c, name)
+		if err != nil {
+			return nil, err
+		}
+
+		d.SetId(id)
+
+		return []*schema.ResourceData{d}, nil
+	}
+}
+
+// importJoinState returns an importer for association resources whose ID is
+// "<left>:<right>". Each part may either be an ID or a name, which is resolved
+// using the corresponding lookup function.
+func importJoinState(
+	leftName, rightName string,
+	lookupLeft, lookupRight func(ctx context.Context, c *client.Client, name string) (string, error),
+) schema.StateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+		providerMeta := meta.(*providerMeta)
+		c := providerMeta.client
+
+		left, right, err := parseCompositeID(d.Id(), leftName, rightName)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isUUID(left) {
+			if left, err = lookupLeft(ctx, c, left); err != nil {
+				return nil, err
+			}
+		}
+
+		if !isUUID(right) {
+			if right, err = lookupRight(ctx, c, right); err != nil {
+				return nil, err
+			}
+		}
+
+		d.SetId(fmt.Sprintf("%s:%s", left, right))
+
+		return []*schema.ResourceData{d}, nil
+	}
+}
+
+// lookupUserID resolves a username to a user ID using the user search endpoint.
+func lookupUserID(ctx context.Context, c *client.Client, username string) (string, error) {
+	users, err := c.GetUsers(ctx, username)
+	if err != nil {
+		return "", fmt.Errorf("failed to search users: %w", err)
+	}
+
+	for _, user := range users {
+		if user.Username == username {
+			return user.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("user with username %s not found", username)
+}
+
+// lookupRoleID resolves a role name to a role ID using the role search endpoint.
+func lookupRoleID(ctx context.Context, c *client.Client, name string) (string, error) {
+	roles, err := c.GetRoles(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to search roles: %w", err)
+	}
+
+	for _, role := range roles {
+		if role.Name == name {
+			return role.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("role with name %s not found", name)
+}
+
+// lookupTargetID resolves a target name to a target ID using the target search endpoint.
+func lookupTargetID(ctx context.Context, c *client.Client, name string) (string, error) {
+	targets, err := c.GetTargets(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to search targets: %w", err)
+	}
+
+	for _, target := range targets {
+		if target.Name == name {
+			return target.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("target with name %s not found", name)
+}
+
+// lookupTargetGroupID resolves a target group name to a target group ID.
+func lookupTargetGroupID(ctx context.Context, c *client.Client, name string) (string, error) {
+	targetGroups, err := c.GetTargetGroups(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list target groups: %w", err)
+	}
+
+	for _, targetGroup := range targetGroups {
+		if targetGroup.Name == name {
+			return targetGroup.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("target group with name %s not found", name)
+}
+
+// labeledCredential is the ID and label of a credential, which is all that is
+// needed to import it by label.
+type labeledCredential struct {
+	ID    string
+	Label string
+}
+
+// importCredentialByLabel resolves a "<user_id>:<credential_id>" import ID of
+// a credential resource, in which the user may be given by username and the
+// credential by label. Labels are looked up among the credentials returned by
+// list, and kind names the credentials in errors.
+func importCredentialByLabel(
+	ctx context.Context,
+	c *client.Client,
+	id, kind string,
+	list func(ctx context.Context, userID string) ([]labeledCredential, error),
+) (string, string, error) {
+	userID, credentialID, err := parseCompositeID(id, "user_id", "credential_id")
+	if err != nil {
+		return "", "", err
+	}
+
+	if !isUUID(userID) {
+		if userID, err = lookupUserID(ctx, c, userID); err != nil {
+			return "", "", err
+		}
+	}
+
+	if isUUID(credentialID) {
+		return userID, credentialID, nil
+	}
+
+	label := credentialID
+
+	creds, err := list(ctx, userID)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get %s credentials: %w", kind, err)
+	}
+
+	credentialID = ""
+	for _, cred := range creds {
+		if cred.Label != label {
+			continue
+		}
+		if credentialID != "" {
+			return "", "", fmt.Errorf("multiple %s credentials with label %s found, import by credential ID instead", kind, label)
+		}
+		credentialID = cred.ID
+	}
+
+	if credentialID == "" {
+		return "", "", fmt.Errorf("%s credential with label %s not found", kind, label)
+	}
+
+	return userID, credentialID, nil
+}
@@ -0,0 +1,166 @@
+// Package provider implements the Terraform provider for Warpgate
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Prefix lengths below which an allowed IP range is considered overly broad.
+const (
+	broadIPv4RangeBits = 8
+	broadIPv6RangeBits = 16
+)
+
+// parseIPRange parses an IP range in CIDR notation or a bare IP address, which
+// is taken as the range of that single address.
+func parseIPRange(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("%q is not a valid CIDR range", s)
+		}
+		if masked := prefix.Masked(); masked != prefix {
+			return netip.Prefix{}, fmt.Errorf("%q has host bits set, did you mean %q?", s, masked)
+		}
+		return prefix, nil
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("%q is neither a valid CIDR range nor an IP address", s)
+	}
+	if addr.Zone() != "" {
+		return netip.Prefix{}, fmt.Errorf("%q must not have an IPv6 zone", s)
+	}
+
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// canonicalIPRange returns the canonical CIDR notation of an IP range, such as
+// "10.0.0.1/32" for "10.0.0.1". Values that don't parse are returned as-is.
+func canonicalIPRange(s string) string {
+	prefix, err := parseIPRange(s)
+	if err != nil {
+		return s
+	}
+	return prefix.String()
+}
+
+// validateIPRange checks that a value is an IP range in CIDR notation or a bare
+// IP address.
+func validateIPRange(v any, k string) ([]string, []error) {
+	if _, err := parseIPRange(v.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	return nil, nil
+}
+
+// warnAllowedIPRanges warns about allowed_ip_ranges entries that are overly
+// broad or overlap an earlier entry. These are valid, but usually mistakes.
+func warnAllowedIPRanges(ctx context.Context, req schema.ValidateResourceConfigFuncRequest, resp *schema.ValidateResourceConfigFuncResponse) {
+	if req.RawConfig.IsNull() || !req.RawConfig.IsKnown() {
+		return
+	}
+
+	ranges := req.RawConfig.GetAttr("allowed_ip_ranges")
+	if ranges.IsNull() || !ranges.IsKnown() {
+		return
+	}
+
+	var prefixes []netip.Prefix
+	var indexes []int
+	var values []string
+	for i, v := range ranges.AsValueSlice() {
+		if v.IsNull() || !v.IsKnown() {
+			continue
+		}
+
+		// Invalid entries are reported by the attribute validation
+		prefix, err := parseIPRange(v.AsString())
+		if err != nil {
+			continue
+		}
+
+		path := cty.GetAttrPath("allowed_ip_ranges").IndexInt(i)
+
+		if msg := broadIPRangeWarning(prefix); msg != "" {
+			resp.Diagnostics = append(resp.Diagnostics, diag.Diagnostic{
+				Severity:      diag.Warning,
+				Summary:       "Overly broad allowed IP range",
+				Detail:        msg,
+				AttributePath: path,
+			})
+		}
+
+		// An identical earlier range is reported in preference to a broader one
+		overlap := -1
+		for j, other := range prefixes {
+			if other == prefix {
+				overlap = j
+				break
+			}
+			if overlap < 0 && prefix.Overlaps(other) {
+				overlap = j
+			}
+		}
+
+		if overlap >= 0 {
+			other, index := prefixes[overlap], indexes[overlap]
+
+			// CIDR ranges only overlap if one contains the other
+			var detail string
+			switch {
+			case prefix == other:
+				detail = fmt.Sprintf("%s is the same range as %s at index %d.", v.AsString(), values[overlap], index)
+			case other.Bits() < prefix.Bits():
+				detail = fmt.Sprintf("%s is contained in %s at index %d, so it has no effect.", prefix, other, index)
+			default:
+				detail = fmt.Sprintf("%s contains %s at index %d, so the latter has no effect.", prefix, other, index)
+			}
+
+			resp.Diagnostics = append(resp.Diagnostics, diag.Diagnostic{
+				Severity:      diag.Warning,
+				Summary:       "Overlapping allowed IP ranges",
+				Detail:        detail,
+				AttributePath: path,
+			})
+		}
+
+		prefixes = append(prefixes, prefix)
+		indexes = append(indexes, i)
+		values = append(values, v.AsString())
+	}
+}
+
+// broadIPRangeWarning returns a warning message if the range is overly broad,
+// or an empty string otherwise.
+func broadIPRangeWarning(prefix netip.Prefix) string {
+	if prefix.Bits() == 0 {
+		return fmt.Sprintf("%s allows connections from any %s address, which is the same as not restricting the user's IP ranges for that address family.", prefix, ipFamily(prefix))
+	}
+
+	limit := broadIPv4RangeBits
+	if prefix.Addr().Is6() {
+		limit = broadIPv6RangeBits
+	}
+	if prefix.Bits() < limit {
+		return fmt.Sprintf("%s allows connections from a large part of the %s address space. Use a narrower range if possible.", prefix, ipFamily(prefix))
+	}
+
+	return ""
+}
+
+// ipFamily returns the name of the address family of a range.
+func ipFamily(prefix netip.Prefix) string {
+	if prefix.Addr().Is4() {
+		return "IPv4"
+	}
+	return "IPv6"
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestCanonicalIPRange(t *testing.T) {
+	for _, tc := range []struct {
+		value    string
+		expected string
+	}{
+		{"10.0.0.1", "10.0.0.1/32"},
+		{"10.0.0.0/8", "10.0.0.0/8"},
+		{"2001:DB8::1", "2001:db8::1/128"},
+		{"2001:0db8:0000::/32", "2001:db8::/32"},
+		{"not an ip", "not an ip"},
+	} {
+		if got := canonicalIPRange(tc.value); got != tc.expected {
+			t.Errorf("canonicalIPRange(%q) = %q, expected %q", tc.value, got, tc.expected)
+		}
+	}
+}
+
+func TestParseIPRangeErrors(t *testing.T) {
+	for _, tc := range []struct {
+		value    string
+		expected string
+	}{
+		{"10.0.0.300", "neither a valid CIDR range nor an IP address"},
+		{"10.0.0.0/33", "not a valid CIDR range"},
+		{"10.0.0.1/8", `did you mean "10.0.0.0/8"?`},
+		{"fe80::1%eth0", "must not have an IPv6 zone"},
+	} {
+		_, err := parseIPRange(tc.value)
+		if err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("parseIPRange(%q) returned error %v, expected it to contain %q", tc.value, err, tc.expected)
+		}
+	}
+}
+
+func TestWarnAllowedIPRanges(t *testing.T) {
+	ranges := []string{"10.0.0.0/8", "10.1.0.0/16", "192.168.1.1", "192.168.1.1/32", "2001:db8::/32", "2000::/8", "0.0.0.0/0"}
+
+	values := make([]cty.Value, len(ranges))
+	for i, r := range ranges {
+		values[i] = cty.StringVal(r)
+	}
+	values = append(values, cty.UnknownVal(cty.String))
+
+	resp := &schema.ValidateResourceConfigFuncResponse{}
+	warnAllowedIPRanges(context.Background(), schema.ValidateResourceConfigFuncRequest{
+		RawConfig: cty.ObjectVal(map[string]cty.Value{
+			"allowed_ip_ranges": cty.ListVal(values),
+		}),
+	}, resp)
+
+	expected := []struct {
+		index  int
+		detail string
+	}{
+		{1, "10.1.0.0/16 is contained in 10.0.0.0/8 at index 0"},
+		{3, "192.168.1.1/32 is the same range as 192.168.1.1 at index 2"},
+		{5, "2000::/8 allows connections from a large part of the IPv6 address space"},
+		{5, "2000::/8 contains 2001:db8::/32 at index 4"},
+		{6, "0.0.0.0/0 allows connections from any IPv4 address"},
+		{6, "0.0.0.0/0 contains 10.0.0.0/8 at index 0"},
+	}
+
+	if len(resp.Diagnostics) != len(expected) {
+		t.Fatalf("expected %d warnings, got %d: %v", len(expected), len(resp.Diagnostics), resp.Diagnostics)
+	}
+
+	for i, e := range expected {
+		d := resp.Diagnostics[i]
+		if d.Severity != diag.Warning {
+			t.Errorf("warning %d: expected a warning, got severity %v", i, d.Severity)
+		}
+		if !d.AttributePath.Equals(cty.GetAttrPath("allowed_ip_ranges").IndexInt(e.index)) {
+			t.Errorf("warning %d: unexpected path %#v", i, d.AttributePath)
+		}
+		if !strings.Contains(d.Detail, e.detail) {
+			t.Errorf("warning %d: detail %q doesn't contain %q", i, d.Detail, e.detail)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Package provider implements the Terraform provider for Warpgate
+package provider
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kubeconfig is the subset of the kubeconfig file format written by
+// renderKubeconfig.
+type kubeconfig struct {
+	APIVersion     string              `yaml:"apiVersion"`
+	Kind           string              `yaml:"kind"`
+	Clusters       []kubeconfigCluster `yaml:"clusters"`
+	Users          []kubeconfigUser    `yaml:"users"`
+	Contexts       []kubeconfigContext `yaml:"contexts"`
+	CurrentContext string              `yaml:"current-context"`
+}
+
+type kubeconfigCluster struct {
+	Name    string `yaml:"name"`
+	Cluster struct {
+		Server string `yaml:"server"`
+	} `yaml:"cluster"`
+}
+
+type kubeconfigUser struct {
+	Name string   `yaml:"name"`
+	User struct{} `yaml:"user"`
+}
+
+type kubeconfigContext struct {
+	Name    string `yaml:"name"`
+	Context struct {
+		Cluster string `yaml:"cluster"`
+		User    string `yaml:"user"`
+	} `yaml:"context"`
+}
+
+// kubernetesProxyURL returns the URL under which Warpgate serves a Kubernetes
+// target: the target name is the first path segment on the Kubernetes
+// listener. The port is added to host unless it already has one.
+func kubernetesProxyURL(host string, port int, targetName string) string {
+	if _, _, err := net.SplitHostPort(host); err != nil && port != 0 {
+		host = net.JoinHostPort(host, strconv.Itoa(port))
+	}
+
+	u := url.URL{
+		Scheme: "https",
+		Host:   host,
+		Path:   "/" + targetName,
+	}
+
+	return u.String()
+}
+
+// renderKubeconfig returns a kubeconfig with a single context that connects
+// as the user to the Kubernetes target through Warpgate. The user entry has no
+// credentials, as the provider has no access to them; they are added with
+// "kubectl config set-credentials".
+func renderKubeconfig(server, targetName, username string) (string, error) {
+	contextName := fmt.Sprintf("%s@%s", username, targetName)
+
+	config := kubeconfig{
+		APIVersion:     "v1",
+		Kind:           "Config",
+		Clusters:       []kubeconfigCluster{{Name: targetName}},
+		Users:          []kubeconfigUser{{Name: username}},
+		Contexts:       []kubeconfigContext{{Name: contextName}},
+		CurrentContext: contextName,
+	}
+	config.Clusters[0].Cluster.Server = server
+	config.Contexts[0].Context.Cluster = targetName
+	config.Contexts[0].Context.User = username
+
+	var out strings.Builder
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(config); err != nil {
+		return "", fmt.Errorf("failed to encode kubeconfig: %w", err)
+	}
+
+	return out.String(), nil
+}
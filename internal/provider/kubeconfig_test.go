@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestKubernetesProxyURL(t *testing.T) {
+	for _, tc := range []struct {
+		host     string
+		port     int
+		target   string
+		expected string
+	}{
+		{"warpgate.example.com", 8443, "cluster", "https://warpgate.example.com:8443/cluster"},
+		{"warpgate.example.com:9443", 8443, "cluster", "https://warpgate.example.com:9443/cluster"},
+		{"warpgate.example.com", 0, "cluster", "https://warpgate.example.com/cluster"},
+		{"::1", 8443, "my cluster", "https://[::1]:8443/my%20cluster"},
+	} {
+		if got := kubernetesProxyURL(tc.host, tc.port, tc.target); got != tc.expected {
+			t.Errorf("kubernetesProxyURL(%q, %d, %q) = %q, expected %q", tc.host, tc.port, tc.target, got, tc.expected)
+		}
+	}
+}
+
+func TestRenderKubeconfig(t *testing.T) {
+	got, err := renderKubeconfig("https://warpgate.example.com:8443/cluster", "cluster", "alice")
+	if err != nil {
+		t.Fatalf("renderKubeconfig returned error: %v", err)
+	}
+
+	expected := `apiVersion: v1
+kind: Config
+clusters:
+  - name: cluster
+    cluster:
+      server: https://warpgate.example.com:8443/cluster
+users:
+  - name: alice
+    user: {}
+contexts:
+  - name: alice@cluster
+    context:
+      cluster: cluster
+      user: alice
+current-context: alice@cluster
+`
+	if got != expected {
+		t.Fatalf("unexpected kubeconfig:\n%s", got)
+	}
+}
@@ -0,0 +1,103 @@
+// Package provider implements the Terraform provider for Warpgate
+package provider
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// Character classes that generated passwords are drawn from. Special
+// characters are limited to ones that need no quoting in common shells and
+// connection strings.
+const (
+	passwordLowerChars   = "abcdefghijklmnopqrstuvwxyz"
+	passwordUpperChars   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	passwordNumericChars = "0123456789"
+	passwordSpecialChars = "!#%*+-_=.:?@^~"
+)
+
+// passwordPolicy describes how a password is generated. Every enabled
+// character class appears at least once in the password.
+type passwordPolicy struct {
+	Length  int
+	Lower   bool
+	Upper   bool
+	Numeric bool
+	Special bool
+}
+
+// generatePassword returns a random password following the policy, using a
+// cryptographically secure random source.
+func generatePassword(policy passwordPolicy) (string, error) {
+	var classes []string
+	if policy.Lower {
+		classes = append(classes, passwordLowerChars)
+	}
+	if policy.Upper {
+		classes = append(classes, passwordUpperChars)
+	}
+	if policy.Numeric {
+		classes = append(classes, passwordNumericChars)
+	}
+	if policy.Special {
+		classes = append(classes, passwordSpecialChars)
+	}
+
+	if len(classes) == 0 {
+		return "", fmt.Errorf("at least one character class must be enabled")
+	}
+	if policy.Length < len(classes) {
+		return "", fmt.Errorf("a length of %d is too short to include all %d enabled character classes", policy.Length, len(classes))
+	}
+
+	var all string
+	for _, class := range classes {
+		all += class
+	}
+
+	password := make([]byte, 0, policy.Length)
+	for _, class := range classes {
+		c, err := randomChar(class)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+	for len(password) < policy.Length {
+		c, err := randomChar(all)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+
+	// Shuffle so that the guaranteed characters are not always in front
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := randomInt(i + 1)
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j] = password[j], password[i]
+	}
+
+	return string(password), nil
+}
+
+// randomChar returns a random character of the given set.
+func randomChar(chars string) (byte, error) {
+	i, err := randomInt(len(chars))
+	if err != nil {
+		return 0, err
+	}
+	return chars[i], nil
+}
+
+// randomInt returns a uniformly distributed random number in [0, n).
+func randomInt(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate random number: %w", err)
+	}
+	return int(i.Int64()), nil
+}
@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratePassword(t *testing.T) {
+	policy := passwordPolicy{Length: 24, Lower: true, Upper: true, Numeric: true, Special: true}
+
+	seen := map[string]bool{}
+	for range 20 {
+		password, err := generatePassword(policy)
+		if err != nil {
+			t.Fatalf("generatePassword returned error: %v", err)
+		}
+
+		if len(password) != policy.Length {
+			t.Fatalf("expected length %d, got %d", policy.Length, len(password))
+		}
+
+		for _, class := range []string{passwordLowerChars, passwordUpperChars, passwordNumericChars, passwordSpecialChars} {
+			if !strings.ContainsAny(password, class) {
+				t.Fatalf("password %q has no character of %q", password, class)
+			}
+		}
+
+		if seen[password] {
+			t.Fatalf("password %q generated twice", password)
+		}
+		seen[password] = true
+	}
+}
+
+func TestGeneratePasswordCharacterClasses(t *testing.T) {
+	password, err := generatePassword(passwordPolicy{Length: 64, Numeric: true})
+	if err != nil {
+		t.Fatalf("generatePassword returned error: %v", err)
+	}
+
+	if strings.Trim(password, passwordNumericChars) != "" {
+		t.Fatalf("expected only digits, got %q", password)
+	}
+}
+
+func TestGeneratePasswordInvalidPolicy(t *testing.T) {
+	if _, err := generatePassword(passwordPolicy{Length: 32}); err == nil {
+		t.Fatal("expected an error without character classes")
+	}
+
+	if _, err := generatePassword(passwordPolicy{Length: 3, Lower: true, Upper: true, Numeric: true, Special: true}); err == nil {
+		t.Fatal("expected an error for a length shorter than the number of classes")
+	}
+}
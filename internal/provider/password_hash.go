@@ -0,0 +1,74 @@
+// Package provider implements the Terraform provider for Warpgate
+package provider
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Parameters of the argon2id hashes of configured passwords kept in the state.
+// They follow the OWASP recommendation for argon2id and are recorded in each
+// hash, so that they can be changed without invalidating existing hashes.
+const (
+	passwordHashTime    = 2
+	passwordHashMemory  = 19 * 1024
+	passwordHashThreads = 1
+	passwordHashKeyLen  = 32
+	passwordHashSaltLen = 16
+)
+
+// hashPassword returns a salted argon2id hash of the password in the PHC string
+// format, e.g. "$argon2id$v=19$m=19456,t=2,p=1$<salt>$<hash>".
+func hashPassword(password string) (string, error) {
+	salt := make([]byte, passwordHashSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, passwordHashTime, passwordHashMemory, passwordHashThreads, passwordHashKeyLen)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, passwordHashMemory, passwordHashTime, passwordHashThreads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// passwordMatchesHash reports whether the password matches a hash returned by
+// hashPassword. Malformed hashes never match.
+func passwordMatchesHash(password, hash string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return false
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false
+	}
+
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false
+	}
+
+	expected, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(expected) == 0 {
+		return false
+	}
+
+	key := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(expected)))
+
+	return subtle.ConstantTimeCompare(key, expected) == 1
+}
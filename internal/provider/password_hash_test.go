@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHashPassword(t *testing.T) {
+	first, err := hashPassword("secret")
+	if err != nil {
+		t.Fatalf("hashPassword returned error: %v", err)
+	}
+
+	if !strings.HasPrefix(first, "$argon2id$v=19$") {
+		t.Fatalf("unexpected hash format: %q", first)
+	}
+
+	if strings.Contains(first, "secret") {
+		t.Fatalf("hash %q contains the password", first)
+	}
+
+	second, err := hashPassword("secret")
+	if err != nil {
+		t.Fatalf("hashPassword returned error: %v", err)
+	}
+
+	if first == second {
+		t.Fatalf("hashes of the same password are not salted: %q", first)
+	}
+
+	if !passwordMatchesHash("secret", first) || !passwordMatchesHash("secret", second) {
+		t.Fatalf("password does not match its hashes")
+	}
+
+	if passwordMatchesHash("other", first) {
+		t.Fatalf("other password matches the hash")
+	}
+}
+
+func TestPasswordMatchesHashMalformed(t *testing.T) {
+	for _, hash := range []string{
+		"",
+		"secret",
+		"$argon2i$v=19$m=19456,t=2,p=1$c2FsdA$aGFzaA",
+		"$argon2id$v=18$m=19456,t=2,p=1$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=x,t=2,p=1$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=19456,t=2,p=1$!!!$aGFzaA",
+		"$argon2id$v=19$m=19456,t=2,p=1$c2FsdA$",
+	} {
+		if passwordMatchesHash("secret", hash) {
+			t.Errorf("malformed hash %q matches", hash)
+		}
+	}
+}
@@ -3,9 +3,15 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/tf5to6server"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
@@ -16,64 +22,112 @@ import (
 // with resources and data sources for managing Warpgate entities.
 func New(version string) func() *schema.Provider {
 	return func() *schema.Provider {
-		p := &schema.Provider{
-			Schema: map[string]*schema.Schema{
-				"host": {
-					Type:        schema.TypeString,
-					Required:    true,
-					DefaultFunc: schema.EnvDefaultFunc("WARPGATE_HOST", nil),
-					Description: "The Warpgate API host URL (e.g., https://warpgate.example.com)",
-				},
-				"insecure_skip_verify": {
-					Type:        schema.TypeBool,
-					Optional:    true,
-					DefaultFunc: schema.EnvDefaultFunc("WARPGATE_INSECURE_SKIP_VERIFY", nil),
-					Description: "Whether to skip the TLS certificate verification (self-signed certificates)",
-				},
-				"token": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Sensitive:   true,
-					DefaultFunc: schema.EnvDefaultFunc("WARPGATE_TOKEN", nil),
-					Description: "API token for authenticating with Warpgate API",
-				},
+		return newProvider(version, &providerMetaCache{})
+	}
+}
+
+// newProvider creates the SDK provider, sharing the provider meta it is
+// configured with through metas.
+func newProvider(version string, metas *providerMetaCache) *schema.Provider {
+	p := &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"host": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("WARPGATE_HOST", nil),
+				Description: "The Warpgate API host URL (e.g., https://warpgate.example.com)",
 			},
-			ResourcesMap: map[string]*schema.Resource{
-				"warpgate_role":                  resourceRole(),
-				"warpgate_user":                  resourceUser(),
-				"warpgate_target":                resourceTarget(),
-				"warpgate_user_role":             resourceUserRole(),
-				"warpgate_target_role":           resourceTargetRole(),
-				"warpgate_target_group":          resourceTargetGroup(),
-				"warpgate_password_credential":   resourcePasswordCredential(),
-				"warpgate_public_key_credential": resourcePublicKeyCredential(),
-				"warpgate_user_sso_credential":   resourceUserSsoCredential(),
-				"warpgate_ticket":                resourceTicket(),
-				"warpgate_parameters":            resourceParameters(),
+			"insecure_skip_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("WARPGATE_INSECURE_SKIP_VERIFY", nil),
+				Description: "Whether to skip the TLS certificate verification (self-signed certificates)",
 			},
-			DataSourcesMap: map[string]*schema.Resource{
-				"warpgate_role":         dataSourceRole(),
-				"warpgate_user":         dataSourceUser(),
-				"warpgate_target":       dataSourceTarget(),
-				"warpgate_ssh_own_keys": dataSourceSSHOwnKeys(),
+			"token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("WARPGATE_TOKEN", nil),
+				Description: "API token for authenticating with Warpgate API",
 			},
-		}
+		},
+		// Resources and data sources migrated to the framework provider
+		// are removed from these maps, see NewFramework
+		ResourcesMap: map[string]*schema.Resource{
+			"warpgate_user":                   resourceUser(),
+			"warpgate_target":                 resourceTarget(),
+			"warpgate_user_role":              resourceUserRole(),
+			"warpgate_user_roles":             resourceUserRoles(),
+			"warpgate_target_role":            resourceTargetRole(),
+			"warpgate_target_roles":           resourceTargetRoles(),
+			"warpgate_target_group":           resourceTargetGroup(),
+			"warpgate_password_credential":    resourcePasswordCredential(),
+			"warpgate_public_key_credential":  resourcePublicKeyCredential(),
+			"warpgate_user_public_keys":       resourceUserPublicKeys(),
+			"warpgate_certificate_credential": resourceCertificateCredential(),
+			"warpgate_user_sso_credential":    resourceUserSsoCredential(),
+			"warpgate_ticket":                 resourceTicket(),
+			"warpgate_parameters":             resourceParameters(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"warpgate_role":              dataSourceRole(),
+			"warpgate_user":              dataSourceUser(),
+			"warpgate_target":            dataSourceTarget(),
+			"warpgate_ssh_own_keys":      dataSourceSSHOwnKeys(),
+			"warpgate_server_info":       dataSourceServerInfo(),
+			"warpgate_stale_credentials": dataSourceStaleCredentials(),
+		},
+	}
+
+	p.ConfigureContextFunc = configure(metas)
+	p.TerraformVersion = "0.13+"
 
-		p.ConfigureContextFunc = configure()
-		p.TerraformVersion = "0.13+"
+	return p
+}
+
+// NewProtoV6ProviderServer returns a function that creates the provider server,
+// which serves the SDK provider returned by New and the framework provider
+// returned by NewFramework as a single provider. Each resource and data source
+// is implemented by exactly one of them.
+func NewProtoV6ProviderServer(ctx context.Context, version string) (func() tfprotov6.ProviderServer, error) {
+	// Both providers are configured with the same settings, so they share
+	// the client and the server information
+	metas := &providerMetaCache{}
+
+	sdkServer, err := tf5to6server.UpgradeServer(ctx, newProvider(version, metas).GRPCProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upgrade the SDK provider server: %w", err)
+	}
+
+	servers := []func() tfprotov6.ProviderServer{
+		func() tfprotov6.ProviderServer { return sdkServer },
+		providerserver.NewProtocol6(&frameworkProvider{version: version, metas: metas}),
+	}
 
-		return p
+	muxServer, err := tf6muxserver.NewMuxServer(ctx, servers...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the provider server: %w", err)
 	}
+
+	return muxServer.ProviderServer, nil
 }
 
 type providerMeta struct {
 	client *client.Client
+
+	// serverInfo and serverVersion describe the Warpgate server. They are nil if
+	// the server could not be queried or did not report its version.
+	serverInfo    *client.ServerInfo
+	serverVersion *client.Version
+
+	// serverInfoErr is the error that prevented retrieving serverInfo, if any.
+	serverInfoErr error
 }
 
 // configure creates a configuration function for the Warpgate provider.
 // It establishes a client connection to the Warpgate API using the provided
 // host and token, and returns a metadata object containing the client and version.
-func configure() func(context.Context, *schema.ResourceData) (any, diag.Diagnostics) {
+func configure(metas *providerMetaCache) func(context.Context, *schema.ResourceData) (any, diag.Diagnostics) {
 	return func(ctx context.Context, d *schema.ResourceData) (any, diag.Diagnostics) {
 		var diags diag.Diagnostics
 
@@ -81,35 +135,108 @@ func configure() func(context.Context, *schema.ResourceData) (any, diag.Diagnost
 		token := d.Get("token").(string)
 		insecureSkipVerify := d.Get("insecure_skip_verify").(bool)
 
-		// Ensure the host has the API path
-		apiPath := "/@warpgate/admin/api"
-		if !strings.Contains(host, apiPath) {
-			if strings.HasSuffix(host, "/") {
-				host = host + strings.TrimPrefix(apiPath, "/")
-			} else {
-				host = host + apiPath
-			}
-		}
-
-		cfg := &client.Config{
-			Host:               host,
-			Token:              token,
-			InsecureSkipVerify: insecureSkipVerify,
-		}
-
-		c, err := client.NewClient(cfg)
+		meta, err := metas.get(ctx, host, token, insecureSkipVerify)
 		if err != nil {
-			return nil, diag.FromErr(fmt.Errorf("error creating client: %w", err))
+			return nil, diag.FromErr(err)
 		}
 
-		meta := &providerMeta{
-			client: c,
+		// The framework provider is configured with the same settings and
+		// leaves reporting this warning to the SDK provider
+		if meta.serverInfoErr != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  serverVersionWarning,
+				Detail:   fmt.Sprintf("Version-dependent features will not be checked before they are sent to the server: %s", meta.serverInfoErr),
+			})
 		}
 
 		return meta, diags
 	}
 }
 
+// serverVersionWarning is the summary of the warning shown when the server
+// version can't be determined.
+const serverVersionWarning = "Unable to determine the Warpgate server version"
+
+// providerMetaKey identifies the provider settings a provider meta was
+// created with.
+type providerMetaKey struct {
+	host               string
+	token              string
+	insecureSkipVerify bool
+}
+
+// providerMetaCache hands the SDK and framework providers, which are configured
+// separately but with the same settings, the same provider meta, so that the
+// client is only created and the server only queried once.
+type providerMetaCache struct {
+	mu    sync.Mutex
+	metas map[providerMetaKey]*providerMeta
+}
+
+// get returns the provider meta for the given settings, creating it on first use.
+func (c *providerMetaCache) get(ctx context.Context, host, token string, insecureSkipVerify bool) (*providerMeta, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := providerMetaKey{host: host, token: token, insecureSkipVerify: insecureSkipVerify}
+	if meta, ok := c.metas[key]; ok {
+		return meta, nil
+	}
+
+	meta, err := newProviderMeta(ctx, host, token, insecureSkipVerify)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.metas == nil {
+		c.metas = make(map[providerMetaKey]*providerMeta)
+	}
+	c.metas[key] = meta
+
+	return meta, nil
+}
+
+// newProviderMeta creates the client for the given provider settings and
+// retrieves the server information.
+func newProviderMeta(ctx context.Context, host, token string, insecureSkipVerify bool) (*providerMeta, error) {
+	if host == "" {
+		return nil, errors.New("the Warpgate host must be set with the host argument or the WARPGATE_HOST environment variable")
+	}
+
+	cfg := &client.Config{
+		Host:               client.AdminAPIURL(host),
+		Token:              token,
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+
+	c, err := client.NewClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating client: %w", err)
+	}
+
+	meta := &providerMeta{
+		client: c,
+	}
+
+	// The server version is only used to produce clearer errors for features
+	// that the server doesn't support, so failing to get it is not fatal
+	info, err := c.GetServerInfo(ctx)
+	if err != nil {
+		meta.serverInfoErr = err
+		return meta, nil
+	}
+
+	meta.serverInfo = info
+	if info.Version != nil {
+		if version, err := client.ParseVersion(*info.Version); err == nil {
+			meta.serverVersion = &version
+		}
+	}
+
+	return meta, nil
+}
+
 // parseCompositeID parses a composite ID in the format "id1:id2" and returns
 // the individual components. Used for importing resources that have composite identifiers.
 func parseCompositeID(id string, part1Name, part2Name string) (string, string, error) {
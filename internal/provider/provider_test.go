@@ -0,0 +1,276 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/warpgatetest"
+)
+
+const testToken = "test-token"
+
+// testAccProtoV6ProviderFactories instantiates the muxed provider for
+// resource.Test cases.
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"warpgate": func() (tfprotov6.ProviderServer, error) {
+		providerServer, err := NewProtoV6ProviderServer(context.Background(), "test")
+		if err != nil {
+			return nil, err
+		}
+		return providerServer(), nil
+	},
+}
+
+// testAccTest runs an acceptance test case. If WARPGATE_HOST is set, the test
+// runs against that Warpgate instance and, like any acceptance test, only when
+// TF_ACC is set. Otherwise it runs unconditionally against an in-process fake
+// server.
+func testAccTest(t *testing.T, tc resource.TestCase) {
+	t.Helper()
+
+	if os.Getenv("WARPGATE_HOST") == "" {
+		testAccStartServer(t)
+		tc.IsUnitTest = true
+	}
+
+	tc.ProtoV6ProviderFactories = testAccProtoV6ProviderFactories
+
+	resource.Test(t, tc)
+}
+
+// testAccStartServer starts a fake Warpgate server for the duration of the test
+// and points the provider at it through the environment.
+func testAccStartServer(t *testing.T) *warpgatetest.Server {
+	t.Helper()
+
+	s := warpgatetest.NewServer(testToken)
+	t.Cleanup(s.Close)
+
+	t.Setenv("WARPGATE_HOST", s.URL)
+	t.Setenv("WARPGATE_TOKEN", testToken)
+
+	return s
+}
+
+// testAccClient returns a client for the Warpgate instance under test, for use
+// in checks that need to look behind the provider's back.
+func testAccClient() (*client.Client, error) {
+	return client.NewClient(&client.Config{
+		Host:               client.AdminAPIURL(os.Getenv("WARPGATE_HOST")),
+		Token:              os.Getenv("WARPGATE_TOKEN"),
+		InsecureSkipVerify: os.Getenv("WARPGATE_INSECURE_SKIP_VERIFY") == "true",
+	})
+}
+
+// testAccExistsFunc reports whether the object backing a resource still exists.
+type testAccExistsFunc func(ctx context.Context, c *client.Client, rs *terraform.ResourceState) (bool, error)
+
+// testAccCheckDestroy returns a CheckDestroy function verifying that no resource
+// of the given type still exists in Warpgate.
+func testAccCheckDestroy(resourceType string, exists testAccExistsFunc) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		c, err := testAccClient()
+		if err != nil {
+			return err
+		}
+
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+
+			found, err := exists(context.Background(), c, rs)
+			if err != nil {
+				return fmt.Errorf("failed to check %s %s: %w", resourceType, rs.Primary.ID, err)
+			}
+			if found {
+				return fmt.Errorf("%s %s still exists", resourceType, rs.Primary.ID)
+			}
+		}
+
+		return nil
+	}
+}
+
+// testAccCheckExists returns a check verifying that the named resource exists in Warpgate.
+func testAccCheckExists(name string, exists testAccExistsFunc) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", name)
+		}
+
+		c, err := testAccClient()
+		if err != nil {
+			return err
+		}
+
+		found, err := exists(context.Background(), c, rs)
+		if err != nil {
+			return fmt.Errorf("failed to check %s: %w", name, err)
+		}
+		if !found {
+			return fmt.Errorf("%s does not exist", name)
+		}
+
+		return nil
+	}
+}
+
+// testAccChangeOutOfBand returns a check that modifies the object backing the
+// named resource directly through the API, to simulate drift. It is used in a
+// step with ExpectNonEmptyPlan, followed by a step that re-applies the config.
+func testAccChangeOutOfBand(name string, change func(ctx context.Context, c *client.Client, rs *terraform.ResourceState) error) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", name)
+		}
+
+		c, err := testAccClient()
+		if err != nil {
+			return err
+		}
+
+		if err := change(context.Background(), c, rs); err != nil {
+			return fmt.Errorf("failed to change %s out of band: %w", name, err)
+		}
+
+		return nil
+	}
+}
+
+// testAccStoreResourceID returns a check saving the ID of the named resource,
+// to compare it in later steps.
+func testAccStoreResourceID(name string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", name)
+		}
+
+		*id = rs.Primary.ID
+
+		return nil
+	}
+}
+
+// testAccCheckResourceIDChanged returns a check verifying that the named
+// resource has been replaced since its ID was saved.
+func testAccCheckResourceIDChanged(name string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", name)
+		}
+
+		if rs.Primary.ID == *id {
+			return fmt.Errorf("%s was not replaced", name)
+		}
+
+		return nil
+	}
+}
+
+// testAccImportStateIDFunc returns an ImportStateIdFunc joining the given
+// attributes of the named resource with colons.
+func testAccImportStateIDFunc(name string, attributes ...string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return "", fmt.Errorf("resource %s not found in state", name)
+		}
+
+		parts := make([]string, len(attributes))
+		for i, attribute := range attributes {
+			parts[i] = rs.Primary.Attributes[attribute]
+		}
+
+		return strings.Join(parts, ":"), nil
+	}
+}
+
+func TestProvider(t *testing.T) {
+	if err := New("test")().InternalValidate(); err != nil {
+		t.Fatalf("provider failed internal validation: %v", err)
+	}
+}
+
+// TestProviderServer verifies that the SDK and framework providers can be
+// served together, which requires identical provider schemas and no resource
+// or data source implemented by both.
+func TestProviderServer(t *testing.T) {
+	ctx := context.Background()
+
+	providerServer, err := NewProtoV6ProviderServer(ctx, "test")
+	if err != nil {
+		t.Fatalf("failed to create provider server: %v", err)
+	}
+
+	resp, err := providerServer().GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("failed to get provider schema: %v", err)
+	}
+
+	for _, d := range resp.Diagnostics {
+		t.Errorf("unexpected diagnostic: %s: %s", d.Summary, d.Detail)
+	}
+
+	if _, ok := resp.ResourceSchemas["warpgate_role"]; !ok {
+		t.Error("warpgate_role is not served")
+	}
+	if _, ok := resp.ResourceSchemas["warpgate_user"]; !ok {
+		t.Error("warpgate_user is not served")
+	}
+}
+
+// TestProviderMetaCache verifies that providers configured with the same
+// settings share the provider meta, and only query the server once.
+func TestProviderMetaCache(t *testing.T) {
+	s := warpgatetest.NewServer(testToken)
+	t.Cleanup(s.Close)
+
+	var infoRequests atomic.Int32
+	handler := s.Config.Handler
+	s.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == client.PublicAPIPath+"/info" {
+			infoRequests.Add(1)
+		}
+		handler.ServeHTTP(w, r)
+	})
+
+	ctx := context.Background()
+	metas := &providerMetaCache{}
+
+	first, err := metas.get(ctx, s.URL, testToken, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := metas.get(ctx, s.URL, testToken, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Error("expected the same provider meta for the same settings")
+	}
+	if n := infoRequests.Load(); n != 1 {
+		t.Errorf("expected the server to be queried once, got %d requests", n)
+	}
+
+	other, err := metas.get(ctx, s.URL, "other-token", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == first {
+		t.Error("expected a separate provider meta for different settings")
+	}
+}
@@ -0,0 +1,324 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+)
+
+func resourceCertificateCredential() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: withFeatureWarnings(resourceCertificateCredentialCreate, certificateCredentialFeatureWarnings),
+		ReadContext:   resourceCertificateCredentialRead,
+		UpdateContext: resourceCertificateCredentialUpdate,
+		DeleteContext: resourceCertificateCredentialDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCertificateCredentialImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"user_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the user to add the certificate credential to",
+			},
+			"label": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "A label for the certificate",
+			},
+			"certificate": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				ExactlyOneOf:     []string{"certificate", "csr"},
+				ValidateFunc:     validatePEM("CERTIFICATE"),
+				DiffSuppressFunc: suppressEquivalentCertificate,
+				Description:      "The PEM client certificate to register. If `csr` is set instead, the certificate issued by Warpgate.",
+			},
+			"csr": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"certificate", "csr"},
+				ValidateFunc: validatePEM("CERTIFICATE REQUEST"),
+				Description:  "A PEM certificate signing request for Warpgate to issue the certificate from. The private key never leaves the client.",
+			},
+			"fingerprint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SHA-256 fingerprint of the certificate, as colon-separated hex",
+			},
+			"expires_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the certificate expires, in RFC 3339 format",
+			},
+			"date_added": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The date the certificate was added",
+			},
+			"last_used": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The date the certificate was last used",
+			},
+		},
+	}
+}
+
+// certificateCredentialFeatureWarnings warns if the server may be too old to
+// support certificate credentials.
+func certificateCredentialFeatureWarnings(d *schema.ResourceData, meta any) diag.Diagnostics {
+	return serverFeatureWarnings(meta, featureCertificateCredentials, nil)
+}
+
+// validatePEM returns a validation function checking that a value is a single
+// PEM block of the given type that parses as such.
+func validatePEM(blockType string) schema.SchemaValidateFunc {
+	return func(v any, k string) ([]string, []error) {
+		block, rest := pem.Decode([]byte(v.(string)))
+		if block == nil || block.Type != blockType {
+			return nil, []error{fmt.Errorf("%s must be a PEM block of type %q", k, blockType)}
+		}
+		if len(bytes.TrimSpace(rest)) > 0 {
+			return nil, []error{fmt.Errorf("%s must contain a single PEM block", k)}
+		}
+
+		var err error
+		switch blockType {
+		case "CERTIFICATE":
+			_, err = x509.ParseCertificate(block.Bytes)
+		case "CERTIFICATE REQUEST":
+			_, err = x509.ParseCertificateRequest(block.Bytes)
+		}
+		if err != nil {
+			return nil, []error{fmt.Errorf("%s is invalid: %w", k, err)}
+		}
+
+		return nil, nil
+	}
+}
+
+// parseCertificatePEM parses the first PEM certificate in s.
+func parseCertificatePEM(s string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(s))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no PEM certificate found")
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// certificateFingerprint returns the SHA-256 fingerprint of a certificate in
+// the colon-separated hex format used by openssl.
+func certificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+
+	return strings.Join(parts, ":")
+}
+
+// suppressEquivalentCertificate suppresses diffs between PEM encodings of the
+// same certificate, such as ones differing in line endings or trailing
+// whitespace.
+func suppressEquivalentCertificate(k, oldValue, newValue string, d *schema.ResourceData) bool {
+	if oldValue == newValue {
+		return true
+	}
+
+	oldCert, err := parseCertificatePEM(oldValue)
+	if err != nil {
+		return false
+	}
+
+	newCert, err := parseCertificatePEM(newValue)
+	if err != nil {
+		return false
+	}
+
+	return oldCert.Equal(newCert)
+}
+
+func resourceCertificateCredentialCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	userID := d.Get("user_id").(string)
+	label := d.Get("label").(string)
+
+	var cred *client.CertificateCredential
+	var err error
+	if csr := d.Get("csr").(string); csr != "" {
+		cred, err = c.IssueCertificateCredential(ctx, userID, label, csr)
+	} else {
+		cred, err = c.AddCertificateCredential(ctx, userID, label, d.Get("certificate").(string))
+	}
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to add certificate credential: %w", err))
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", userID, cred.ID))
+
+	return resourceCertificateCredentialRead(ctx, d, meta)
+}
+
+func resourceCertificateCredentialRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	var diags diag.Diagnostics
+
+	userID, credID, err := parseCompositeID(d.Id(), "user_id", "credential_id")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	creds, err := c.GetCertificateCredentials(ctx, userID)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to get certificate credentials: %w", err))
+	}
+
+	var cred *client.CertificateCredential
+	for i := range creds {
+		if creds[i].ID == credID {
+			cred = &creds[i]
+			break
+		}
+	}
+
+	if cred == nil {
+		d.SetId("")
+		return diags
+	}
+
+	if err := d.Set("user_id", userID); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set user_id: %w", err))
+	}
+
+	if err := d.Set("label", cred.Label); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set label: %w", err))
+	}
+
+	// Setting the certificate read back from Warpgate detects certificates
+	// replaced outside of Terraform
+	if err := d.Set("certificate", cred.CertificatePEM); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set certificate: %w", err))
+	}
+
+	fingerprint, expiresAt := "", ""
+	if cert, err := parseCertificatePEM(cred.CertificatePEM); err == nil {
+		fingerprint = certificateFingerprint(cert)
+		expiresAt = cert.NotAfter.UTC().Format(time.RFC3339)
+	} else {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Warning,
+			Summary:       "Unreadable certificate",
+			Detail:        fmt.Sprintf("The certificate of credential %s returned by Warpgate can't be parsed, so fingerprint and expires_at are left empty: %s", credID, err),
+			AttributePath: cty.GetAttrPath("certificate"),
+		})
+	}
+
+	if err := d.Set("fingerprint", fingerprint); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set fingerprint: %w", err))
+	}
+
+	if err := d.Set("expires_at", expiresAt); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set expires_at: %w", err))
+	}
+
+	if err := d.Set("date_added", cred.DateAdded); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set date_added: %w", err))
+	}
+
+	if cred.LastUsed != "" {
+		if err := d.Set("last_used", cred.LastUsed); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to set last_used: %w", err))
+		}
+	}
+
+	return diags
+}
+
+func resourceCertificateCredentialUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	userID, credID, err := parseCompositeID(d.Id(), "user_id", "credential_id")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if _, err := c.UpdateCertificateCredential(ctx, userID, credID, d.Get("label").(string)); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to update certificate credential: %w", err))
+	}
+
+	return resourceCertificateCredentialRead(ctx, d, meta)
+}
+
+func resourceCertificateCredentialDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	var diags diag.Diagnostics
+
+	userID, credID, err := parseCompositeID(d.Id(), "user_id", "credential_id")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := c.DeleteCertificateCredential(ctx, userID, credID); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to delete certificate credential: %w", err))
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// resourceCertificateCredentialImport handles the import of an existing
+// certificate credential. The import ID should be in the format
+// "user_id:credential_id" or "username:label".
+func resourceCertificateCredentialImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	userID, credentialID, err := importCredentialByLabel(ctx, c, d.Id(), "certificate", func(ctx context.Context, userID string) ([]labeledCredential, error) {
+		creds, err := c.GetCertificateCredentials(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		labeled := make([]labeledCredential, len(creds))
+		for i, cred := range creds {
+			labeled[i] = labeledCredential{ID: cred.ID, Label: cred.Label}
+		}
+		return labeled, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", userID, credentialID))
+	if err := d.Set("user_id", userID); err != nil {
+		return nil, fmt.Errorf("failed to set user_id: %w", err)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
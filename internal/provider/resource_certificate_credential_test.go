@@ -0,0 +1,186 @@
+package provider
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+)
+
+func TestAccCertificateCredential(t *testing.T) {
+	username := acctest.RandomWithPrefix("tf-acc")
+	certificate, cert := testAccCertificate(t, username)
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_certificate_credential", testAccCertificateCredentialExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCertificateCredentialConfig(username, "laptop", "certificate", certificate),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_certificate_credential.test", testAccCertificateCredentialExists),
+					resource.TestCheckResourceAttr("warpgate_certificate_credential.test", "label", "laptop"),
+					resource.TestCheckResourceAttr("warpgate_certificate_credential.test", "fingerprint", certificateFingerprint(cert)),
+					resource.TestCheckResourceAttr("warpgate_certificate_credential.test", "expires_at", cert.NotAfter.UTC().Format(time.RFC3339)),
+					resource.TestCheckResourceAttrSet("warpgate_certificate_credential.test", "date_added"),
+				),
+			},
+			{
+				// Whitespace differences in the PEM don't cause a diff
+				Config:   testAccCertificateCredentialConfig(username, "laptop", "certificate", strings.ReplaceAll(certificate, "\n", "\r\n")),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccCertificateCredentialConfig(username, "workstation", "certificate", certificate),
+				Check:  resource.TestCheckResourceAttr("warpgate_certificate_credential.test", "label", "workstation"),
+			},
+			{
+				ResourceName:      "warpgate_certificate_credential.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "warpgate_certificate_credential.test",
+				ImportState:       true,
+				ImportStateId:     username + ":workstation",
+				ImportStateVerify: true,
+			},
+			{
+				Config:             testAccCertificateCredentialConfig(username, "workstation", "certificate", certificate),
+				Check:              testAccChangeOutOfBand("warpgate_certificate_credential.test", testAccCertificateCredentialDelete),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccCertificateCredentialConfig(username, "workstation", "certificate", certificate),
+				Check:  testAccCheckExists("warpgate_certificate_credential.test", testAccCertificateCredentialExists),
+			},
+		},
+	})
+}
+
+func TestAccCertificateCredential_csr(t *testing.T) {
+	username := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_certificate_credential", testAccCertificateCredentialExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCertificateCredentialConfig(username, "kubectl", "csr", testAccCertificateRequest(t, username)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_certificate_credential.test", testAccCertificateCredentialExists),
+					resource.TestMatchResourceAttr("warpgate_certificate_credential.test", "certificate", regexp.MustCompile(`^-----BEGIN CERTIFICATE-----\n`)),
+					resource.TestMatchResourceAttr("warpgate_certificate_credential.test", "fingerprint", regexp.MustCompile(`^([0-9A-F]{2}:){31}[0-9A-F]{2}$`)),
+					resource.TestCheckResourceAttrSet("warpgate_certificate_credential.test", "expires_at"),
+				),
+			},
+			{
+				ResourceName:            "warpgate_certificate_credential.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"csr"},
+			},
+		},
+	})
+}
+
+func testAccCertificateCredentialConfig(username, label, attribute, value string) string {
+	return fmt.Sprintf(`
+resource "warpgate_user" "test" {
+  username = %q
+}
+
+resource "warpgate_certificate_credential" "test" {
+  user_id = warpgate_user.test.id
+  label   = %q
+  %s      = %q
+}
+`, username, label, attribute, value)
+}
+
+// testAccCertificate returns a self-signed client certificate in PEM format.
+func testAccCertificate(t *testing.T, commonName string) (string, *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), cert
+}
+
+// testAccCertificateRequest returns a certificate signing request in PEM format.
+func testAccCertificateRequest(t *testing.T, commonName string) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: commonName},
+	}, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate request: %v", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+}
+
+func testAccCertificateCredentialExists(ctx context.Context, c *client.Client, rs *terraform.ResourceState) (bool, error) {
+	userID, credentialID, _ := strings.Cut(rs.Primary.ID, ":")
+
+	user, err := c.GetUser(ctx, userID)
+	if err != nil || user == nil {
+		return false, err
+	}
+
+	creds, err := c.GetCertificateCredentials(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, cred := range creds {
+		if cred.ID == credentialID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func testAccCertificateCredentialDelete(ctx context.Context, c *client.Client, rs *terraform.ResourceState) error {
+	userID, credentialID, _ := strings.Cut(rs.Primary.ID, ":")
+	return c.DeleteCertificateCredential(ctx, userID, credentialID)
+}
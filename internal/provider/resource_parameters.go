@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -13,9 +14,9 @@ import (
 // TEST
 func resourceParameters() *schema.Resource {
 	return &schema.Resource{
-		CreateContext: resourceParametersCreate,
+		CreateContext: withFeatureWarnings(resourceParametersCreate, parametersFeatureWarnings),
 		ReadContext:   resourceParametersRead,
-		UpdateContext: resourceParametersUpdate,
+		UpdateContext: withFeatureWarnings(resourceParametersUpdate, parametersFeatureWarnings),
 		DeleteContext: resourceParametersDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
@@ -107,6 +108,16 @@ func resourceParameters() *schema.Resource {
 	}
 }
 
+// parametersFeatureWarnings warns about parameters the server may be too old to
+// support.
+func parametersFeatureWarnings(d *schema.ResourceData, meta any) diag.Diagnostics {
+	if d.Get("record_scp").(bool) {
+		return serverFeatureWarnings(meta, featureRecordSCP, cty.GetAttrPath("record_scp"))
+	}
+
+	return nil
+}
+
 // resourceParametersCreate handles the creation of Warpgate parameters (singleton resource)
 func resourceParametersCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	providerMeta := meta.(*providerMeta)
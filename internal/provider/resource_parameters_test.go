@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+)
+
+// Parameters are global, so running this test against a real Warpgate instance
+// changes its settings. Destroying the resource leaves the parameters in place,
+// so there is nothing for CheckDestroy to verify.
+func TestAccParameters(t *testing.T) {
+	testAccTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: testAccParametersConfig(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("warpgate_parameters.test", "id", "parameters"),
+					resource.TestCheckResourceAttr("warpgate_parameters.test", "allow_own_credential_management", "false"),
+					resource.TestCheckResourceAttr("warpgate_parameters.test", "ssh_client_auth_publickey", "true"),
+				),
+			},
+			{
+				Config: testAccParametersConfig(true),
+				Check:  resource.TestCheckResourceAttr("warpgate_parameters.test", "allow_own_credential_management", "true"),
+			},
+			{
+				ResourceName:      "warpgate_parameters.test",
+				ImportState:       true,
+				ImportStateId:     "parameters",
+				ImportStateVerify: true,
+			},
+			{
+				Config:             testAccParametersConfig(true),
+				Check:              testAccChangeOutOfBand("warpgate_parameters.test", testAccParametersDisableOwnCredentialManagement),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccParametersConfig(true),
+				Check:  resource.TestCheckResourceAttr("warpgate_parameters.test", "allow_own_credential_management", "true"),
+			},
+		},
+	})
+}
+
+func testAccParametersConfig(allowOwnCredentialManagement bool) string {
+	return fmt.Sprintf(`
+resource "warpgate_parameters" "test" {
+  allow_own_credential_management = %t
+  ssh_client_auth_publickey       = true
+  ssh_client_auth_password        = true
+  show_session_menu               = true
+}
+`, allowOwnCredentialManagement)
+}
+
+func testAccParametersDisableOwnCredentialManagement(ctx context.Context, c *client.Client, rs *terraform.ResourceState) error {
+	params, err := c.GetParameters(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := client.ParametersUpdateRequest(*params)
+	req.AllowOwnCredentialManagement = false
+
+	_, err = c.UpdateParameters(ctx, &req)
+	return err
+}
@@ -4,19 +4,41 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
 )
 
 func resourcePasswordCredential() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourcePasswordCredentialCreate,
 		ReadContext:   resourcePasswordCredentialRead,
+		UpdateContext: resourcePasswordCredentialUpdate,
 		DeleteContext: resourcePasswordCredentialDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourcePasswordCredentialImport,
 		},
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourcePasswordCredentialV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourcePasswordCredentialStateUpgradeV0,
+			},
+		},
+		Schema:        passwordCredentialSchema(),
+		CustomizeDiff: planPasswordRotation,
+	}
+}
+
+// resourcePasswordCredentialV0 describes version 0 of the state, which kept
+// the plaintext password. It must not change with the current schema.
+func resourcePasswordCredentialV0() *schema.Resource {
+	return &schema.Resource{
 		Schema: map[string]*schema.Schema{
 			"user_id": {
 				Type:        schema.TypeString,
@@ -35,6 +57,296 @@ func resourcePasswordCredential() *schema.Resource {
 	}
 }
 
+// resourcePasswordCredentialStateUpgradeV0 replaces the plaintext password in
+// version 0 of the state with its hash.
+func resourcePasswordCredentialStateUpgradeV0(ctx context.Context, rawState map[string]any, meta any) (map[string]any, error) {
+	password, _ := rawState["password"].(string)
+	if password == "" {
+		return rawState, nil
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	rawState["password"] = ""
+	rawState["password_hash"] = hash
+
+	return rawState, nil
+}
+
+func passwordCredentialSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"user_id": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The ID of the user to add the password credential to",
+		},
+		"password": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			ExactlyOneOf:     []string{"password", "password_wo", "generate_password"},
+			DiffSuppressFunc: suppressHashedPasswordDiff,
+			Description:      "The password for authentication. Only a salted hash of it is stored in the state, in `password_hash`, but saved plan files contain it in plain text, so prefer `password_wo`. Changing it rotates the credential in place.",
+		},
+		"password_hash": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "A salted argon2id hash of `password`, used to detect changes to it",
+		},
+		"password_wo": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			WriteOnly:    true,
+			Sensitive:    true,
+			ExactlyOneOf: []string{"password", "password_wo", "generate_password"},
+			RequiredWith: []string{"password_wo_version"},
+			Description:  "The password for authentication, as a write-only attribute that is never stored in the plan or the state. This is the recommended way to set the password. Requires Terraform 1.11 or later.",
+		},
+		"password_wo_version": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			RequiredWith: []string{"password_wo"},
+			ValidateFunc: validation.IntAtLeast(1),
+			Description:  "The version of `password_wo`. Change it to rotate the credential in place to the current value of `password_wo`.",
+		},
+		"generate_password": {
+			Type:         schema.TypeList,
+			Optional:     true,
+			MaxItems:     1,
+			ExactlyOneOf: []string{"password", "password_wo", "generate_password"},
+			Description:  "Have the provider generate the password. The password is available in `generated_password`.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"length": {
+						Type:         schema.TypeInt,
+						Optional:     true,
+						Default:      32,
+						ValidateFunc: validation.IntBetween(12, 256),
+						Description:  "The length of the password",
+					},
+					"lower": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     true,
+						Description: "Include lowercase letters",
+					},
+					"upper": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     true,
+						Description: "Include uppercase letters",
+					},
+					"numeric": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     true,
+						Description: "Include digits",
+					},
+					"special": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     true,
+						Description: "Include special characters (" + passwordSpecialChars + ")",
+					},
+				},
+			},
+		},
+		"rotation_trigger": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			RequiredWith: []string{"generate_password"},
+			Description:  "An arbitrary value. Changing it rotates the generated password.",
+		},
+		"rotate_after": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			RequiredWith: []string{"generate_password"},
+			ValidateFunc: validatePositiveDuration,
+			Description:  "Rotate the generated password when it is older than this duration (e.g. `720h`). The rotation happens on the first apply after the duration has passed.",
+		},
+		"generated_password": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Sensitive:   true,
+			Description: "The generated password",
+		},
+		"rotated_at": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "When the generated password was created, in RFC 3339 format",
+		},
+	}
+}
+
+// suppressHashedPasswordDiff suppresses the diff between the blank password in
+// the state and the configured password if it matches password_hash.
+func suppressHashedPasswordDiff(k, oldValue, newValue string, d *schema.ResourceData) bool {
+	return !passwordChanged(oldValue, newValue, d.Get("password_hash").(string))
+}
+
+// passwordChanged reports whether the configured password differs from the one
+// recorded in the state, either as plaintext or as hash.
+func passwordChanged(oldValue, newValue, hash string) bool {
+	if oldValue != "" || newValue == "" {
+		return oldValue != newValue
+	}
+
+	return !passwordMatchesHash(newValue, hash)
+}
+
+// validatePositiveDuration validates that a value is a positive Go duration
+// such as "720h".
+func validatePositiveDuration(v any, k string) ([]string, []error) {
+	duration, err := time.ParseDuration(v.(string))
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s must be a duration such as \"720h\": %w", k, err)}
+	}
+	if duration <= 0 {
+		return nil, []error{fmt.Errorf("%s must be positive", k)}
+	}
+	return nil, nil
+}
+
+// planPasswordRotation marks the generated password or the password hash as
+// unknown when the credential is rotated, so that the plan shows the rotation.
+func planPasswordRotation(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	// Switching to a configured password drops the generated one
+	oldPolicy, newPolicy := d.GetChange("generate_password")
+	if len(oldPolicy.([]any)) > 0 && len(newPolicy.([]any)) == 0 {
+		for _, key := range []string{"generated_password", "rotated_at"} {
+			if err := d.SetNew(key, ""); err != nil {
+				return err
+			}
+		}
+	}
+
+	// HasChange ignores DiffSuppressFunc, so the password is compared here
+	oldPassword, newPassword := d.GetChange("password")
+
+	// Only password has a hash, which switching to another mode drops. The
+	// configuration is checked, as a suppressed diff hides the password.
+	if config := d.GetRawConfig(); !config.IsNull() && config.GetAttr("password").IsNull() && d.Get("password_hash").(string) != "" {
+		if err := d.SetNew("password_hash", ""); err != nil {
+			return err
+		}
+	}
+
+	if newPassword.(string) != "" && (passwordChanged(oldPassword.(string), newPassword.(string), d.Get("password_hash").(string)) || d.HasChange("generate_password")) {
+		return d.SetNewComputed("password_hash")
+	}
+
+	rotate, err := rotationDue(d, "generate_password")
+	if err != nil || !rotate {
+		return err
+	}
+
+	if err := d.SetNewComputed("generated_password"); err != nil {
+		return err
+	}
+	return d.SetNewComputed("rotated_at")
+}
+
+// rotationDue reports whether a secret must be generated for an existing
+// credential: because the generation block was just added, because it or
+// rotation_trigger changed, or because the generated secret is older than
+// rotate_after. It only runs at plan time, as the age check depends on the
+// current time; the apply follows the plan through rotationPlanned.
+func rotationDue(d *schema.ResourceDiff, block string) (bool, error) {
+	oldPolicy, newPolicy := d.GetChange(block)
+	if len(newPolicy.([]any)) == 0 {
+		return false, nil
+	}
+	if len(oldPolicy.([]any)) == 0 {
+		return true, nil
+	}
+
+	if d.HasChange(block) || d.HasChange("rotation_trigger") {
+		return true, nil
+	}
+
+	rotateAfter := d.Get("rotate_after").(string)
+	if rotateAfter == "" {
+		return false, nil
+	}
+
+	duration, err := time.ParseDuration(rotateAfter)
+	if err != nil {
+		return false, fmt.Errorf("invalid rotate_after: %w", err)
+	}
+
+	rotatedAt, err := time.Parse(time.RFC3339, d.Get("rotated_at").(string))
+	if err != nil {
+		// Imported credentials have no rotation time
+		return false, nil
+	}
+
+	return time.Now().After(rotatedAt.Add(duration)), nil
+}
+
+// rotationPlanned reports whether the plan rotates the generated secret, which
+// it marks by leaving rotated_at unknown. Deciding from the plan rather than
+// the current time keeps a rotate_after that expires between plan and apply
+// from rotating the secret without it being planned.
+func rotationPlanned(d *schema.ResourceData) bool {
+	plan := d.GetRawPlan()
+	if plan.IsNull() || !plan.IsKnown() {
+		return false
+	}
+	return !plan.GetAttr("rotated_at").IsKnown()
+}
+
+// expandPasswordPolicy converts the generate_password block to a password policy.
+func expandPasswordPolicy(list []any) passwordPolicy {
+	m := list[0].(map[string]any)
+
+	return passwordPolicy{
+		Length:  m["length"].(int),
+		Lower:   m["lower"].(bool),
+		Upper:   m["upper"].(bool),
+		Numeric: m["numeric"].(bool),
+		Special: m["special"].(bool),
+	}
+}
+
+// addGeneratedPassword generates a password for the user according to the
+// generate_password block, adds it as a new credential and records it in the
+// resource data.
+func addGeneratedPassword(ctx context.Context, c *client.Client, d *schema.ResourceData, userID string) error {
+	password, err := generatePassword(expandPasswordPolicy(d.Get("generate_password").([]any)))
+	if err != nil {
+		return fmt.Errorf("failed to generate password: %w", err)
+	}
+
+	cred, err := c.AddPasswordCredential(ctx, userID, password)
+	if err != nil {
+		return fmt.Errorf("failed to add password credential: %w", err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", userID, cred.ID))
+
+	if err := d.Set("generated_password", password); err != nil {
+		return fmt.Errorf("failed to set generated_password: %w", err)
+	}
+
+	if err := d.Set("rotated_at", time.Now().UTC().Format(time.RFC3339)); err != nil {
+		return fmt.Errorf("failed to set rotated_at: %w", err)
+	}
+
+	if err := d.Set("password_hash", ""); err != nil {
+		return fmt.Errorf("failed to set password_hash: %w", err)
+	}
+
+	return nil
+}
+
 func resourcePasswordCredentialCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	providerMeta := meta.(*providerMeta)
 	c := providerMeta.client
@@ -42,22 +354,149 @@ func resourcePasswordCredentialCreate(ctx context.Context, d *schema.ResourceDat
 	var diags diag.Diagnostics
 
 	userID := d.Get("user_id").(string)
+
+	if _, ok := d.GetOk("generate_password"); ok {
+		if err := addGeneratedPassword(ctx, c, d, userID); err != nil {
+			return diag.FromErr(err)
+		}
+		return diags
+	}
+
+	if err := addConfiguredPassword(ctx, c, d, userID); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+// addConfiguredPassword adds the value of password or password_wo as a new
+// credential of the user. Only the hash of password is recorded in the
+// resource data.
+func addConfiguredPassword(ctx context.Context, c *client.Client, d *schema.ResourceData, userID string) error {
 	password := d.Get("password").(string)
 
+	var hash string
+	if password != "" {
+		var err error
+		if hash, err = hashPassword(password); err != nil {
+			return fmt.Errorf("failed to hash password: %w", err)
+		}
+	} else {
+		var err error
+		if password, err = writeOnlyString(d, cty.GetAttrPath("password_wo")); err != nil {
+			return err
+		}
+	}
+
 	cred, err := c.AddPasswordCredential(ctx, userID, password)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to add password credential: %w", err))
+		return fmt.Errorf("failed to add password credential: %w", err)
 	}
 
 	d.SetId(fmt.Sprintf("%s:%s", userID, cred.ID))
 
-	return diags
+	// The state keeps a blank password that differs from the configuration,
+	// which Terraform only tolerates, with a logged warning, from providers
+	// built on the legacy SDK. password_hash stands in for it in diffs.
+	if err := d.Set("password", ""); err != nil {
+		return fmt.Errorf("failed to set password: %w", err)
+	}
+
+	if err := d.Set("password_hash", hash); err != nil {
+		return fmt.Errorf("failed to set password_hash: %w", err)
+	}
+
+	// Clear the attributes of a previously generated password
+	if d.Get("generated_password").(string) != "" {
+		if err := d.Set("generated_password", ""); err != nil {
+			return fmt.Errorf("failed to set generated_password: %w", err)
+		}
+
+		if err := d.Set("rotated_at", ""); err != nil {
+			return fmt.Errorf("failed to set rotated_at: %w", err)
+		}
+	}
+
+	return nil
 }
 
 func resourcePasswordCredentialRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	// Implementation would check if credential still exists
-	// TODO: We can't read back the password but can verify the credential exists
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	var diags diag.Diagnostics
+
+	// Parse the ID to get user_id and credential_id
+	parts := strings.Split(d.Id(), ":")
+	if len(parts) != 2 {
+		return diag.Errorf("invalid ID format: %s (expected user_id:credential_id)", d.Id())
+	}
+
+	userID := parts[0]
+	credID := parts[1]
+
+	// The password itself can't be read back, but we can verify the credential still exists
+	creds, err := c.GetPasswordCredentials(ctx, userID)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to get password credentials: %w", err))
+	}
+
+	found := false
+	for _, cred := range creds {
+		if cred.ID == credID {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		d.SetId("")
+		return diags
+	}
+
+	if err := d.Set("user_id", userID); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set user_id: %w", err))
+	}
+
+	return diags
+}
+
+// resourcePasswordCredentialUpdate rotates the credential in place when the
+// configured password changes or the plan rotates the generated password.
+// The new password is added before the old one is deleted, so that the user
+// is never left without a password. Other changes only affect the state.
+func resourcePasswordCredentialUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
 	var diags diag.Diagnostics
+
+	_, generated := d.GetOk("generate_password")
+	rotateGenerated := generated && rotationPlanned(d)
+	rotateConfigured := !generated && (d.HasChange("password") || d.HasChange("password_wo_version") || d.HasChange("generate_password"))
+
+	if !rotateGenerated && !rotateConfigured {
+		return diags
+	}
+
+	userID, oldCredID, err := parseCompositeID(d.Id(), "user_id", "credential_id")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if rotateGenerated {
+		err = addGeneratedPassword(ctx, c, d, userID)
+	} else {
+		err = addConfiguredPassword(ctx, c, d, userID)
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := c.DeletePasswordCredential(ctx, userID, oldCredID); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to delete the previous password credential: %w", err))
+	}
+
 	return diags
 }
 
@@ -85,3 +524,29 @@ func resourcePasswordCredentialDelete(ctx context.Context, d *schema.ResourceDat
 
 	return diags
 }
+
+// resourcePasswordCredentialImport handles the import of an existing password credential.
+// The import ID should be in the format "user_id:credential_id", where the user ID
+// may be replaced by the username. The password is write-only and is not imported.
+func resourcePasswordCredentialImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	userID, credentialID, err := parseCompositeID(d.Id(), "user_id", "credential_id")
+	if err != nil {
+		return nil, err
+	}
+
+	if !isUUID(userID) {
+		if userID, err = lookupUserID(ctx, c, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", userID, credentialID))
+	if err := d.Set("user_id", userID); err != nil {
+		return nil, fmt.Errorf("failed to set user_id: %w", err)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
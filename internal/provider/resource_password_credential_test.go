@@ -0,0 +1,426 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/warpgatetest"
+)
+
+func TestAccPasswordCredential(t *testing.T) {
+	username := acctest.RandomWithPrefix("tf-acc")
+
+	var firstID string
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_password_credential", testAccPasswordCredentialExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPasswordCredentialConfig(username, "first-password"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_password_credential.test", testAccPasswordCredentialExists),
+					resource.TestCheckResourceAttrPair("warpgate_password_credential.test", "user_id", "warpgate_user.test", "id"),
+					resource.TestCheckResourceAttr("warpgate_password_credential.test", "password", ""),
+					testAccCheckPasswordHash("warpgate_password_credential.test", "first-password"),
+					testAccStoreResourceID("warpgate_password_credential.test", &firstID),
+				),
+			},
+			{
+				// Changing the password rotates the credential in place
+				Config: testAccPasswordCredentialConfig(username, "second-password"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_password_credential.test", testAccPasswordCredentialExists),
+					testAccCheckResourceIDChanged("warpgate_password_credential.test", &firstID),
+					testAccCheckPasswordCredentialCount(username, 1),
+					testAccCheckPasswordHash("warpgate_password_credential.test", "second-password"),
+				),
+			},
+			{
+				ResourceName:            "warpgate_password_credential.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password", "password_hash"},
+			},
+			{
+				ResourceName:            "warpgate_password_credential.test",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccPasswordCredentialImportIDByUsername(username),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password", "password_hash"},
+			},
+			{
+				Config:             testAccPasswordCredentialConfig(username, "second-password"),
+				Check:              testAccChangeOutOfBand("warpgate_password_credential.test", testAccPasswordCredentialDelete),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccPasswordCredentialConfig(username, "second-password"),
+				Check:  testAccCheckExists("warpgate_password_credential.test", testAccPasswordCredentialExists),
+			},
+		},
+	})
+}
+
+func testAccPasswordCredentialConfig(username, password string) string {
+	return fmt.Sprintf(`
+resource "warpgate_user" "test" {
+  username = %q
+}
+
+resource "warpgate_password_credential" "test" {
+  user_id  = warpgate_user.test.id
+  password = %q
+}
+`, username, password)
+}
+
+// testAccPasswordCredentialImportIDByUsername returns the import ID of the test
+// credential using the username instead of the user ID.
+func testAccPasswordCredentialImportIDByUsername(username string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources["warpgate_password_credential.test"]
+		if !ok {
+			return "", fmt.Errorf("resource warpgate_password_credential.test not found in state")
+		}
+
+		_, credentialID, _ := strings.Cut(rs.Primary.ID, ":")
+
+		return username + ":" + credentialID, nil
+	}
+}
+
+func testAccPasswordCredentialExists(ctx context.Context, c *client.Client, rs *terraform.ResourceState) (bool, error) {
+	userID, credentialID, _ := strings.Cut(rs.Primary.ID, ":")
+
+	user, err := c.GetUser(ctx, userID)
+	if err != nil || user == nil {
+		return false, err
+	}
+
+	creds, err := c.GetPasswordCredentials(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, cred := range creds {
+		if cred.ID == credentialID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// testAccCheckPasswordHash returns a check verifying that password_hash is a
+// hash of the password.
+func testAccCheckPasswordHash(name, password string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", name)
+		}
+
+		if !passwordMatchesHash(password, rs.Primary.Attributes["password_hash"]) {
+			return fmt.Errorf("password_hash of %s does not match %q", name, password)
+		}
+
+		return nil
+	}
+}
+
+func TestResourcePasswordCredentialStateUpgradeV0(t *testing.T) {
+	// A state written by version 0 of the resource, upgraded the way Terraform
+	// does it
+	server := schema.NewGRPCProviderServer(New("test")())
+	resp, err := server.UpgradeResourceState(context.Background(), &tfprotov5.UpgradeResourceStateRequest{
+		TypeName: "warpgate_password_credential",
+		Version:  0,
+		RawState: &tfprotov5.RawState{
+			JSON: []byte(`{"id":"user:credential","user_id":"user","password":"secret"}`),
+		},
+	})
+	if err != nil {
+		t.Fatalf("state upgrade returned error: %v", err)
+	}
+	for _, d := range resp.Diagnostics {
+		t.Fatalf("state upgrade returned diagnostic: %s: %s", d.Summary, d.Detail)
+	}
+
+	state, err := msgpack.Unmarshal(resp.UpgradedState.MsgPack, resourcePasswordCredential().CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatalf("failed to decode the upgraded state: %v", err)
+	}
+
+	for attr, expected := range map[string]string{
+		"id":      "user:credential",
+		"user_id": "user",
+	} {
+		if actual := state.GetAttr(attr).AsString(); actual != expected {
+			t.Errorf("expected %s %q, got %q", attr, expected, actual)
+		}
+	}
+
+	if password := state.GetAttr("password"); !password.IsNull() && password.AsString() != "" {
+		t.Fatalf("expected the password to be removed, got %q", password.AsString())
+	}
+
+	if hash := state.GetAttr("password_hash").AsString(); !passwordMatchesHash("secret", hash) {
+		t.Fatalf("password_hash %q does not match the password", hash)
+	}
+}
+
+func testAccPasswordCredentialDelete(ctx context.Context, c *client.Client, rs *terraform.ResourceState) error {
+	userID, credentialID, _ := strings.Cut(rs.Primary.ID, ":")
+	return c.DeletePasswordCredential(ctx, userID, credentialID)
+}
+
+func TestAccPasswordCredential_writeOnly(t *testing.T) {
+	username := acctest.RandomWithPrefix("tf-acc")
+
+	var firstID string
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_password_credential", testAccPasswordCredentialExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPasswordCredentialWriteOnlyConfig(username, "first-password", 1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_password_credential.test", testAccPasswordCredentialExists),
+					resource.TestCheckResourceAttr("warpgate_password_credential.test", "password", ""),
+					resource.TestCheckNoResourceAttr("warpgate_password_credential.test", "password_wo"),
+					resource.TestCheckResourceAttr("warpgate_password_credential.test", "password_wo_version", "1"),
+					testAccStoreResourceID("warpgate_password_credential.test", &firstID),
+				),
+			},
+			{
+				// Without a version bump, a new value is not applied
+				Config: testAccPasswordCredentialWriteOnlyConfig(username, "second-password", 1),
+				Check:  resource.TestCheckResourceAttrPtr("warpgate_password_credential.test", "id", &firstID),
+			},
+			{
+				// Bumping the version rotates the credential in place
+				Config: testAccPasswordCredentialWriteOnlyConfig(username, "second-password", 2),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_password_credential.test", testAccPasswordCredentialExists),
+					testAccCheckPasswordCredentialCount(username, 1),
+					resource.TestCheckResourceAttr("warpgate_password_credential.test", "password_wo_version", "2"),
+					testAccCheckResourceIDChanged("warpgate_password_credential.test", &firstID),
+				),
+			},
+		},
+	})
+}
+
+func testAccPasswordCredentialWriteOnlyConfig(username, password string, version int) string {
+	return fmt.Sprintf(`
+resource "warpgate_user" "test" {
+  username = %q
+}
+
+resource "warpgate_password_credential" "test" {
+  user_id             = warpgate_user.test.id
+  password_wo         = %q
+  password_wo_version = %d
+}
+`, username, password, version)
+}
+
+func TestAccPasswordCredential_generated(t *testing.T) {
+	username := acctest.RandomWithPrefix("tf-acc")
+
+	var firstID, secondID string
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_password_credential", testAccPasswordCredentialExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPasswordCredentialGeneratedConfig(username, "first", "3s"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_password_credential.test", testAccPasswordCredentialExists),
+					resource.TestMatchResourceAttr("warpgate_password_credential.test", "generated_password", regexp.MustCompile(`^[a-z0-9]{20}$`)),
+					resource.TestCheckResourceAttrSet("warpgate_password_credential.test", "rotated_at"),
+					testAccStoreResourceID("warpgate_password_credential.test", &firstID),
+				),
+			},
+			{
+				// Changing the trigger rotates the password in place
+				Config: testAccPasswordCredentialGeneratedConfig(username, "second", "3s"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_password_credential.test", testAccPasswordCredentialExists),
+					testAccCheckResourceIDChanged("warpgate_password_credential.test", &firstID),
+					testAccCheckPasswordCredentialCount(username, 1),
+					testAccStoreResourceID("warpgate_password_credential.test", &secondID),
+				),
+			},
+			{
+				// Once rotate_after has passed, the next apply rotates the password
+				PreConfig: func() { time.Sleep(4 * time.Second) },
+				Config:    testAccPasswordCredentialGeneratedConfig(username, "second", "3s"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceIDChanged("warpgate_password_credential.test", &secondID),
+					testAccCheckPasswordCredentialCount(username, 1),
+				),
+			},
+			{
+				// Switching to a configured password drops the generated one
+				Config: testAccPasswordCredentialConfig(username, "configured-password"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("warpgate_password_credential.test", "generated_password", ""),
+					resource.TestCheckResourceAttr("warpgate_password_credential.test", "rotated_at", ""),
+					testAccCheckPasswordHash("warpgate_password_credential.test", "configured-password"),
+					testAccCheckPasswordCredentialCount(username, 1),
+				),
+			},
+			{
+				Config:   testAccPasswordCredentialConfig(username, "configured-password"),
+				PlanOnly: true,
+			},
+			{
+				// Switching to a write-only password drops the hash
+				Config: testAccPasswordCredentialWriteOnlyConfig(username, "write-only-password", 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("warpgate_password_credential.test", "password_hash", ""),
+					testAccCheckPasswordCredentialCount(username, 1),
+				),
+			},
+			{
+				// And switching back to a generated password generates a new one
+				Config: testAccPasswordCredentialGeneratedConfig(username, "second", "3s"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("warpgate_password_credential.test", "generated_password", regexp.MustCompile(`^[a-z0-9]{20}$`)),
+					resource.TestCheckResourceAttrSet("warpgate_password_credential.test", "rotated_at"),
+					testAccCheckPasswordCredentialCount(username, 1),
+				),
+			},
+		},
+	})
+}
+
+// TestResourcePasswordCredentialUpdateFollowsPlan verifies that a
+// rotate_after expiring between the plan and the apply doesn't rotate the
+// password without the rotation having been planned.
+func TestResourcePasswordCredentialUpdateFollowsPlan(t *testing.T) {
+	s := warpgatetest.NewServer(testToken)
+	t.Cleanup(s.Close)
+
+	c, err := client.NewClient(&client.Config{Host: client.AdminAPIURL(s.URL), Token: testToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	user, err := c.CreateUser(ctx, &client.UserCreateRequest{Username: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cred, err := c.AddPasswordCredential(ctx, user.ID, "old-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := resourcePasswordCredential()
+	state := &terraform.InstanceState{
+		ID: fmt.Sprintf("%s:%s", user.ID, cred.ID),
+		Attributes: map[string]string{
+			"id":                          fmt.Sprintf("%s:%s", user.ID, cred.ID),
+			"user_id":                     user.ID,
+			"generate_password.#":         "1",
+			"generate_password.0.length":  "32",
+			"generate_password.0.lower":   "true",
+			"generate_password.0.upper":   "true",
+			"generate_password.0.numeric": "true",
+			"generate_password.0.special": "true",
+			"rotate_after":                "1h",
+			"generated_password":          "old-password",
+			"rotated_at":                  time.Now().Add(-2 * time.Hour).Format(time.RFC3339),
+		},
+	}
+
+	// rotate_after has passed, but the plan kept rotated_at known
+	plan, err := state.AttrsAsObjectValue(r.CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.RawPlan = plan
+
+	d := r.Data(state)
+	if diags := resourcePasswordCredentialUpdate(ctx, d, &providerMeta{client: c}); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if d.Id() != state.ID {
+		t.Errorf("expected the ID to stay %s, got %s", state.ID, d.Id())
+	}
+	if got := d.Get("generated_password").(string); got != "old-password" {
+		t.Errorf("expected the generated password to be unchanged, got %q", got)
+	}
+
+	creds, err := c.GetPasswordCredentials(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(creds) != 1 || creds[0].ID != cred.ID {
+		t.Errorf("expected only the original password credential, got %+v", creds)
+	}
+}
+
+func testAccPasswordCredentialGeneratedConfig(username, trigger, rotateAfter string) string {
+	return fmt.Sprintf(`
+resource "warpgate_user" "test" {
+  username = %q
+}
+
+resource "warpgate_password_credential" "test" {
+  user_id = warpgate_user.test.id
+
+  generate_password {
+    length  = 20
+    upper   = false
+    special = false
+  }
+
+  rotation_trigger = %q
+  rotate_after     = %q
+}
+`, username, trigger, rotateAfter)
+}
+
+// testAccCheckPasswordCredentialCount returns a check verifying how many
+// password credentials the user has.
+func testAccCheckPasswordCredentialCount(username string, count int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		c, err := testAccClient()
+		if err != nil {
+			return err
+		}
+
+		userID, err := lookupUserID(context.Background(), c, username)
+		if err != nil {
+			return err
+		}
+
+		creds, err := c.GetPasswordCredentials(context.Background(), userID)
+		if err != nil {
+			return err
+		}
+
+		if len(creds) != count {
+			return fmt.Errorf("expected %d password credentials, got %d", count, len(creds))
+		}
+
+		return nil
+	}
+}
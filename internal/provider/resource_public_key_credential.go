@@ -4,9 +4,13 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+	"golang.org/x/crypto/ssh"
 )
 
 func resourcePublicKeyCredential() *schema.Resource {
@@ -16,7 +20,7 @@ func resourcePublicKeyCredential() *schema.Resource {
 		UpdateContext: resourcePublicKeyCredentialUpdate,
 		DeleteContext: resourcePublicKeyCredentialDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourcePublicKeyCredentialImport,
 		},
 		Schema: map[string]*schema.Schema{
 			"user_id": {
@@ -31,9 +35,88 @@ func resourcePublicKeyCredential() *schema.Resource {
 				Description: "A label for the public key",
 			},
 			"public_key": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ExactlyOneOf:     []string{"public_key", "generate_key_pair"},
+				ValidateFunc:     validatePublicKey,
+				DiffSuppressFunc: suppressEquivalentPublicKey,
+				Description:      "The public key, in OpenSSH authorized_keys, RFC 4716, PEM (SubjectPublicKeyInfo or PKCS #1) or JWK format. Keys are converted to the OpenSSH format before they are sent to Warpgate. DSA keys and RSA keys shorter than 2048 bits are rejected. The comment is not sent to Warpgate, so changing it, the whitespace or the format doesn't cause a diff. With `generate_key_pair`, this is the generated public key.",
+			},
+			"generate_key_pair": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MaxItems:     1,
+				ExactlyOneOf: []string{"public_key", "generate_key_pair"},
+				Description:  "Have the provider generate the key pair. The private key is available in `private_key_openssh` and `private_key_pem`.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"algorithm": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      keyPairAlgorithmEd25519,
+							ValidateFunc: validation.StringInSlice([]string{keyPairAlgorithmEd25519, keyPairAlgorithmRSA}, false),
+							Description:  "The key algorithm, `ed25519` or `rsa`",
+						},
+						"rsa_bits": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      4096,
+							ValidateFunc: validation.IntInSlice(rsaKeyPairBits),
+							Description:  "The size of RSA keys in bits, `2048`, `3072` or `4096`",
+						},
+					},
+				},
+			},
+			"rotation_trigger": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"generate_key_pair"},
+				Description:  "An arbitrary value. Changing it rotates the generated key pair.",
+			},
+			"rotate_after": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"generate_key_pair"},
+				ValidateFunc: validatePositiveDuration,
+				Description:  "Rotate the generated key pair when it is older than this duration (e.g. `2160h`). The rotation happens on the first apply after the duration has passed.",
+			},
+			"private_key_openssh": {
 				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The OpenSSH public key",
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The generated private key, in the OpenSSH format",
+			},
+			"private_key_pem": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The generated private key, in PKCS #8 PEM format",
+			},
+			"rotated_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the generated key pair was created, in RFC 3339 format",
+			},
+			"public_key_format": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The format `public_key` is configured in: `openssh`, `rfc4716`, `pem` or `jwk`. Imported keys are in the `openssh` format.",
+			},
+			"fingerprint_sha256": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SHA-256 fingerprint of the public key, in the format shown by `ssh-keygen -l`",
+			},
+			"key_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the public key, such as `ssh-ed25519`",
+			},
+			"key_bits": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The size of the public key in bits",
 			},
 			"date_added": {
 				Type:        schema.TypeString,
@@ -46,7 +129,140 @@ func resourcePublicKeyCredential() *schema.Resource {
 				Description: "The date the key was last used",
 			},
 		},
+		CustomizeDiff: planPublicKeyAttributes,
+	}
+}
+
+// planPublicKeyAttributes plans the attributes derived from the public key, so
+// that they are known at plan time unless the key itself isn't.
+func planPublicKeyAttributes(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	derived := []string{"public_key_format", "fingerprint_sha256", "key_type", "key_bits"}
+
+	if _, ok := d.GetOk("generate_key_pair"); ok {
+		return planKeyPairRotation(d)
+	}
+
+	// The attributes of a previously generated key pair are cleared
+	for _, key := range []string{"private_key_openssh", "private_key_pem", "rotated_at"} {
+		if d.Get(key).(string) != "" {
+			if err := d.SetNew(key, ""); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !d.NewValueKnown("public_key") {
+		for _, key := range derived {
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Invalid keys are reported by the attribute validation
+	o, n := d.GetChange("public_key")
+	key, format, err := parsePublicKeyFormat(n.(string))
+	if err != nil {
+		return nil
+	}
+
+	if d.Get("public_key_format").(string) != format {
+		if err := d.SetNew("public_key_format", format); err != nil {
+			return err
+		}
+	}
+
+	if d.Id() != "" && normalizePublicKey(o.(string)) == normalizePublicKey(n.(string)) {
+		return nil
+	}
+
+	if err := d.SetNew("fingerprint_sha256", ssh.FingerprintSHA256(key)); err != nil {
+		return err
+	}
+	if err := d.SetNew("key_type", key.Type()); err != nil {
+		return err
+	}
+	return d.SetNew("key_bits", publicKeyBits(key))
+}
+
+// planKeyPairRotation marks the public key, its derived attributes and the
+// private key as unknown when a key pair is generated, so that the plan shows
+// the rotation.
+func planKeyPairRotation(d *schema.ResourceDiff) error {
+	if d.Id() != "" {
+		rotate, err := rotationDue(d, "generate_key_pair")
+		if err != nil || !rotate {
+			return err
+		}
+	}
+
+	// Generated public keys are always in the OpenSSH format
+	if d.Get("public_key_format").(string) != publicKeyFormatOpenSSH {
+		if err := d.SetNew("public_key_format", publicKeyFormatOpenSSH); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range []string{"public_key", "fingerprint_sha256", "key_type", "key_bits", "private_key_openssh", "private_key_pem", "rotated_at"} {
+		if err := d.SetNewComputed(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandKeyPairPolicy converts the generate_key_pair block to a key pair
+// policy. The label is used as the comment of the private key.
+func expandKeyPairPolicy(list []any, label string) keyPairPolicy {
+	m := list[0].(map[string]any)
+
+	return keyPairPolicy{
+		Algorithm: m["algorithm"].(string),
+		RSABits:   m["rsa_bits"].(int),
+		Comment:   label,
+	}
+}
+
+// addGeneratedKeyPair generates a key pair according to the generate_key_pair
+// block, adds its public key as a new credential of the user and records the
+// key pair in the resource data.
+func addGeneratedKeyPair(ctx context.Context, c *client.Client, d *schema.ResourceData, userID string) error {
+	label := d.Get("label").(string)
+
+	pair, err := generateKeyPair(expandKeyPairPolicy(d.Get("generate_key_pair").([]any), label))
+	if err != nil {
+		return fmt.Errorf("failed to generate key pair: %w", err)
+	}
+
+	cred, err := c.AddPublicKeyCredential(ctx, userID, label, pair.PublicKey)
+	if err != nil {
+		return fmt.Errorf("failed to add public key credential: %w", err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", userID, cred.ID))
+
+	if err := d.Set("public_key", pair.PublicKey); err != nil {
+		return fmt.Errorf("failed to set public_key: %w", err)
+	}
+
+	if err := d.Set("public_key_format", publicKeyFormatOpenSSH); err != nil {
+		return fmt.Errorf("failed to set public_key_format: %w", err)
+	}
+
+	if err := d.Set("private_key_openssh", pair.PrivateKeyOpenSSH); err != nil {
+		return fmt.Errorf("failed to set private_key_openssh: %w", err)
 	}
+
+	if err := d.Set("private_key_pem", pair.PrivateKeyPEM); err != nil {
+		return fmt.Errorf("failed to set private_key_pem: %w", err)
+	}
+
+	if err := d.Set("rotated_at", time.Now().UTC().Format(time.RFC3339)); err != nil {
+		return fmt.Errorf("failed to set rotated_at: %w", err)
+	}
+
+	return nil
 }
 
 func resourcePublicKeyCredentialCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
@@ -54,8 +270,16 @@ func resourcePublicKeyCredentialCreate(ctx context.Context, d *schema.ResourceDa
 	c := providerMeta.client
 
 	userID := d.Get("user_id").(string)
+
+	if _, ok := d.GetOk("generate_key_pair"); ok {
+		if err := addGeneratedKeyPair(ctx, c, d, userID); err != nil {
+			return diag.FromErr(err)
+		}
+		return resourcePublicKeyCredentialRead(ctx, d, meta)
+	}
+
 	label := d.Get("label").(string)
-	publicKey := d.Get("public_key").(string)
+	publicKey := normalizePublicKey(d.Get("public_key").(string))
 
 	cred, err := c.AddPublicKeyCredential(ctx, userID, label, publicKey)
 	if err != nil {
@@ -105,6 +329,31 @@ func resourcePublicKeyCredentialRead(ctx context.Context, d *schema.ResourceData
 				return diag.FromErr(fmt.Errorf("failed to set public_key: %w", err))
 			}
 
+			// The format isn't known to Warpgate, which always returns OpenSSH
+			// keys, so only imported keys get a default
+			if d.Get("public_key_format").(string) == "" {
+				if err := d.Set("public_key_format", publicKeyFormatOpenSSH); err != nil {
+					return diag.FromErr(fmt.Errorf("failed to set public_key_format: %w", err))
+				}
+			}
+
+			fingerprint, keyType, keyBits := "", "", 0
+			if key, err := parsePublicKey(cred.OpensshPublicKey); err == nil {
+				fingerprint, keyType, keyBits = ssh.FingerprintSHA256(key), key.Type(), publicKeyBits(key)
+			}
+
+			if err := d.Set("fingerprint_sha256", fingerprint); err != nil {
+				return diag.FromErr(fmt.Errorf("failed to set fingerprint_sha256: %w", err))
+			}
+
+			if err := d.Set("key_type", keyType); err != nil {
+				return diag.FromErr(fmt.Errorf("failed to set key_type: %w", err))
+			}
+
+			if err := d.Set("key_bits", keyBits); err != nil {
+				return diag.FromErr(fmt.Errorf("failed to set key_bits: %w", err))
+			}
+
 			if err := d.Set("date_added", cred.DateAdded); err != nil {
 				return diag.FromErr(fmt.Errorf("failed to set date_added: %w", err))
 			}
@@ -126,6 +375,10 @@ func resourcePublicKeyCredentialRead(ctx context.Context, d *schema.ResourceData
 	return diags
 }
 
+// resourcePublicKeyCredentialUpdate updates the credential in place, or rotates
+// a generated key pair when the plan rotates it. The new key is added before
+// the old one is deleted, so that automation using the key is never left
+// without a valid one.
 func resourcePublicKeyCredentialUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	providerMeta := meta.(*providerMeta)
 	c := providerMeta.client
@@ -139,11 +392,33 @@ func resourcePublicKeyCredentialUpdate(ctx context.Context, d *schema.ResourceDa
 	userID := parts[0]
 	credID := parts[1]
 
+	if _, ok := d.GetOk("generate_key_pair"); ok && rotationPlanned(d) {
+		if err := addGeneratedKeyPair(ctx, c, d, userID); err != nil {
+			return diag.FromErr(err)
+		}
+
+		// The state now tracks the new credential, so the previous one has to
+		// be cleaned up by hand if it can't be deleted
+		if err := c.DeletePublicKeyCredential(ctx, userID, credID); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to delete the previous public key credential %s of user %s, which must be deleted manually: %w", credID, userID, err))
+		}
+
+		return resourcePublicKeyCredentialRead(ctx, d, meta)
+	}
+
+	// Clear the attributes of a previously generated key pair
+	if _, ok := d.GetOk("generate_key_pair"); !ok {
+		for _, key := range []string{"private_key_openssh", "private_key_pem", "rotated_at"} {
+			if err := d.Set(key, ""); err != nil {
+				return diag.FromErr(fmt.Errorf("failed to set %s: %w", key, err))
+			}
+		}
+	}
+
 	label := d.Get("label").(string)
-	publicKey := d.Get("public_key").(string)
+	publicKey := normalizePublicKey(d.Get("public_key").(string))
 
-	_, err := c.UpdatePublicKeyCredential(ctx, userID, credID, label, publicKey)
-	if err != nil {
+	if _, err := c.UpdatePublicKeyCredential(ctx, userID, credID, label, publicKey); err != nil {
 		return diag.FromErr(fmt.Errorf("failed to update public key credential: %w", err))
 	}
 
@@ -174,3 +449,40 @@ func resourcePublicKeyCredentialDelete(ctx context.Context, d *schema.ResourceDa
 
 	return diags
 }
+
+// resourcePublicKeyCredentialImport handles the import of an existing public key credential.
+// The import ID should be in the format "user_id:credential_id" or "username:label".
+func resourcePublicKeyCredentialImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	userID, credentialID, err := importCredentialByLabel(ctx, c, d.Id(), "public key", func(ctx context.Context, userID string) ([]labeledCredential, error) {
+		creds, err := c.GetPublicKeyCredentials(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		labeled := make([]labeledCredential, len(creds))
+		for i, cred := range creds {
+			labeled[i] = labeledCredential{ID: cred.ID, Label: cred.Label}
+		}
+		return labeled, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", userID, credentialID))
+	if err := d.Set("user_id", userID); err != nil {
+		return nil, fmt.Errorf("failed to set user_id: %w", err)
+	}
+
+	// The private key of imported credentials is unknown to the provider
+	for _, key := range []string{"private_key_openssh", "private_key_pem", "rotated_at"} {
+		if err := d.Set(key, ""); err != nil {
+			return nil, fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
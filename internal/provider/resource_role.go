@@ -4,135 +4,189 @@ package provider
 import (
 	"context"
 	"fmt"
-
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
 )
 
-// resourceRole creates and returns a schema for the role resource.
-func resourceRole() *schema.Resource {
-	return &schema.Resource{
-		CreateContext: resourceRoleCreate,
-		ReadContext:   resourceRoleRead,
-		UpdateContext: resourceRoleUpdate,
-		DeleteContext: resourceRoleDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
-		Schema: map[string]*schema.Schema{
-			"name": {
-				Type:         schema.TypeString,
-				Required:     true,
-				Description:  "The name of the role",
-				ValidateFunc: validation.StringLenBetween(1, 255),
+// roleResource manages a Warpgate role. It was the first resource migrated
+// from the SDK; its schema is unchanged, so existing state needs no upgrade.
+type roleResource struct {
+	meta *providerMeta
+}
+
+// roleResourceModel maps the role resource schema.
+type roleResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+}
+
+var (
+	_ resource.ResourceWithConfigure   = &roleResource{}
+	_ resource.ResourceWithImportState = &roleResource{}
+)
+
+// newRoleResource creates the role resource.
+func newRoleResource() resource.Resource {
+	return &roleResource{}
+}
+
+// Metadata returns the role resource type name.
+func (r *roleResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_role"
+}
+
+// Schema returns the schema for the role resource.
+func (r *roleResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the role",
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(1, 255),
+				},
 			},
-			"description": {
-				Type:        schema.TypeString,
+			// The SDK stored an empty string for an unset description, which
+			// the default preserves
+			"description": schema.StringAttribute{
 				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
 				Description: "The description of the role",
 			},
 		},
 	}
 }
 
-// resourceRoleCreate handles the creation of a new role in Warpgate based on
-// the provided resource data.
-func resourceRoleCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	providerMeta := meta.(*providerMeta)
-	c := providerMeta.client
-
-	var diags diag.Diagnostics
+// Configure stores the provider metadata for use in the CRUD methods.
+func (r *roleResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.meta = providerMetaFromData(req.ProviderData, &resp.Diagnostics)
+}
 
-	name := d.Get("name").(string)
-	description := d.Get("description").(string)
+// Create handles the creation of a new role in Warpgate based on the planned
+// values.
+func (r *roleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	c := r.meta.client
 
-	req := &client.RoleCreateRequest{
-		Name:        name,
-		Description: description,
+	var plan roleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	role, err := c.CreateRole(ctx, req)
+	role, err := c.CreateRole(ctx, &client.RoleCreateRequest{
+		Name:        plan.Name.ValueString(),
+		Description: plan.Description.ValueString(),
+	})
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to create role: %w", err))
+		resp.Diagnostics.AddError("Error creating role", fmt.Sprintf("failed to create role: %s", err))
+		return
 	}
 
-	d.SetId(role.ID)
+	plan.ID = types.StringValue(role.ID)
 
-	return diags
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
-// resourceRoleRead retrieves the role data from Warpgate and updates the
-// Terraform state accordingly.
-func resourceRoleRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	providerMeta := meta.(*providerMeta)
-	c := providerMeta.client
-
-	var diags diag.Diagnostics
+// Read retrieves the role data from Warpgate and updates the Terraform state
+// accordingly.
+func (r *roleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	c := r.meta.client
 
-	id := d.Id()
+	var state roleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	role, err := c.GetRole(ctx, id)
+	role, err := c.GetRole(ctx, state.ID.ValueString())
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to read role: %w", err))
+		resp.Diagnostics.AddError("Error reading role", fmt.Sprintf("failed to read role: %s", err))
+		return
 	}
 
-	// If the role was not found, return nil to indicate that the resource no longer exists
+	// If the role was not found, remove it from the state so it is recreated
 	if role == nil {
-		d.SetId("")
-		return diags
+		resp.State.RemoveResource(ctx)
+		return
 	}
 
-	if err := d.Set("name", role.Name); err != nil {
-		return diag.FromErr(fmt.Errorf("failed to set name: %w", err))
-	}
+	state.Name = types.StringValue(role.Name)
+	state.Description = types.StringValue(role.Description)
 
-	if err := d.Set("description", role.Description); err != nil {
-		return diag.FromErr(fmt.Errorf("failed to set description: %w", err))
-	}
-
-	return diags
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 }
 
-// resourceRoleUpdate handles the update of an existing role in Warpgate based on
-// the provided resource data changes.
-func resourceRoleUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	providerMeta := meta.(*providerMeta)
-	c := providerMeta.client
-
-	id := d.Id()
-	name := d.Get("name").(string)
-	description := d.Get("description").(string)
+// Update handles the update of an existing role in Warpgate based on the
+// planned values.
+func (r *roleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	c := r.meta.client
 
-	req := &client.RoleCreateRequest{
-		Name:        name,
-		Description: description,
+	var plan roleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	_, err := c.UpdateRole(ctx, id, req)
+	role, err := c.UpdateRole(ctx, plan.ID.ValueString(), &client.RoleCreateRequest{
+		Name:        plan.Name.ValueString(),
+		Description: plan.Description.ValueString(),
+	})
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to update role: %w", err))
+		resp.Diagnostics.AddError("Error updating role", fmt.Sprintf("failed to update role: %s", err))
+		return
 	}
 
-	return resourceRoleRead(ctx, d, meta)
-}
+	plan.Name = types.StringValue(role.Name)
+	plan.Description = types.StringValue(role.Description)
 
-// resourceRoleDelete removes a role from Warpgate based on the resource data.
-func resourceRoleDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	providerMeta := meta.(*providerMeta)
-	c := providerMeta.client
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
 
-	var diags diag.Diagnostics
+// Delete removes a role from Warpgate.
+func (r *roleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	c := r.meta.client
 
-	id := d.Id()
+	var state roleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	err := c.DeleteRole(ctx, id)
-	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to delete role: %w", err))
+	if err := c.DeleteRole(ctx, state.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Error deleting role", fmt.Sprintf("failed to delete role: %s", err))
 	}
+}
 
-	d.SetId("")
+// ImportState imports a role by ID, or by name using the "name=" prefix.
+func (r *roleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id := req.ID
+
+	if name, ok := strings.CutPrefix(req.ID, importByNamePrefix); ok {
+		var err error
+		id, err = lookupRoleID(ctx, r.meta.client, name)
+		if err != nil {
+			resp.Diagnostics.AddError("Error importing role", err.Error())
+			return
+		}
+	}
 
-	return diags
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
 }
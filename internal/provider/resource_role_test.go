@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+)
+
+func TestAccRole(t *testing.T) {
+	name := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_role", testAccRoleExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRoleConfig(name, "Developers"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_role.test", testAccRoleExists),
+					resource.TestCheckResourceAttrSet("warpgate_role.test", "id"),
+					resource.TestCheckResourceAttr("warpgate_role.test", "name", name),
+					resource.TestCheckResourceAttr("warpgate_role.test", "description", "Developers"),
+				),
+			},
+			{
+				Config: testAccRoleConfig(name+"-renamed", "Engineers"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("warpgate_role.test", "name", name+"-renamed"),
+					resource.TestCheckResourceAttr("warpgate_role.test", "description", "Engineers"),
+				),
+			},
+			{
+				ResourceName:      "warpgate_role.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "warpgate_role.test",
+				ImportState:       true,
+				ImportStateId:     "name=" + name + "-renamed",
+				ImportStateVerify: true,
+			},
+			{
+				Config:             testAccRoleConfig(name+"-renamed", "Engineers"),
+				Check:              testAccChangeOutOfBand("warpgate_role.test", testAccRoleDelete),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccRoleConfig(name+"-renamed", "Engineers"),
+				Check:  testAccCheckExists("warpgate_role.test", testAccRoleExists),
+			},
+		},
+	})
+}
+
+func testAccRoleConfig(name, description string) string {
+	return fmt.Sprintf(`
+resource "warpgate_role" "test" {
+  name        = %q
+  description = %q
+}
+`, name, description)
+}
+
+func testAccRoleExists(ctx context.Context, c *client.Client, rs *terraform.ResourceState) (bool, error) {
+	role, err := c.GetRole(ctx, rs.Primary.ID)
+	return role != nil, err
+}
+
+func testAccRoleDelete(ctx context.Context, c *client.Client, rs *terraform.ResourceState) error {
+	return c.DeleteRole(ctx, rs.Primary.ID)
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -15,12 +16,12 @@ import (
 // resourceTarget creates and returns a schema for the target resource.
 func resourceTarget() *schema.Resource {
 	return &schema.Resource{
-		CreateContext: resourceTargetCreate,
+		CreateContext: withFeatureWarnings(resourceTargetCreate, targetFeatureWarnings),
 		ReadContext:   resourceTargetRead,
-		UpdateContext: resourceTargetUpdate,
+		UpdateContext: withFeatureWarnings(resourceTargetUpdate, targetFeatureWarnings),
 		DeleteContext: resourceTargetDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStateByName(lookupTargetID),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -87,10 +88,27 @@ func resourceTarget() *schema.Resource {
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
 									"password": {
-										Type:        schema.TypeString,
-										Required:    true,
-										Sensitive:   true,
-										Description: "The password for SSH authentication",
+										Type:         schema.TypeString,
+										Optional:     true,
+										Sensitive:    true,
+										ExactlyOneOf: []string{"ssh_options.0.password_auth.0.password", "ssh_options.0.password_auth.0.password_wo"},
+										Description:  "The password for SSH authentication",
+									},
+									"password_wo": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										WriteOnly:    true,
+										Sensitive:    true,
+										ExactlyOneOf: []string{"ssh_options.0.password_auth.0.password", "ssh_options.0.password_auth.0.password_wo"},
+										RequiredWith: []string{"ssh_options.0.password_auth.0.password_wo_version"},
+										Description:  "The password for SSH authentication, as a write-only attribute that is never stored in the state. Requires Terraform 1.11 or later.",
+									},
+									"password_wo_version": {
+										Type:         schema.TypeInt,
+										Optional:     true,
+										RequiredWith: []string{"ssh_options.0.password_auth.0.password_wo"},
+										ValidateFunc: validation.IntAtLeast(1),
+										Description:  "The version of `password_wo`. Change it to send the current value of `password_wo` to Warpgate.",
 									},
 								},
 							},
@@ -185,10 +203,27 @@ func resourceTarget() *schema.Resource {
 							Description: "The MySQL username",
 						},
 						"password": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Sensitive:   true,
-							Description: "The MySQL password",
+							Type:          schema.TypeString,
+							Optional:      true,
+							Sensitive:     true,
+							ConflictsWith: []string{"mysql_options.0.password_wo"},
+							Description:   "The MySQL password",
+						},
+						"password_wo": {
+							Type:          schema.TypeString,
+							Optional:      true,
+							WriteOnly:     true,
+							Sensitive:     true,
+							ConflictsWith: []string{"mysql_options.0.password"},
+							RequiredWith:  []string{"mysql_options.0.password_wo_version"},
+							Description:   "The MySQL password, as a write-only attribute that is never stored in the state. Requires Terraform 1.11 or later.",
+						},
+						"password_wo_version": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							RequiredWith: []string{"mysql_options.0.password_wo"},
+							ValidateFunc: validation.IntAtLeast(1),
+							Description:  "The version of `password_wo`. Change it to send the current value of `password_wo` to Warpgate.",
 						},
 						"tls": {
 							Type:        schema.TypeList,
@@ -245,10 +280,27 @@ func resourceTarget() *schema.Resource {
 							Description:  "The PostgreSQL protocol version to request. Valid values: 3.0, 3.2",
 						},
 						"password": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Sensitive:   true,
-							Description: "The PostgreSQL password",
+							Type:          schema.TypeString,
+							Optional:      true,
+							Sensitive:     true,
+							ConflictsWith: []string{"postgres_options.0.password_wo"},
+							Description:   "The PostgreSQL password",
+						},
+						"password_wo": {
+							Type:          schema.TypeString,
+							Optional:      true,
+							WriteOnly:     true,
+							Sensitive:     true,
+							ConflictsWith: []string{"postgres_options.0.password"},
+							RequiredWith:  []string{"postgres_options.0.password_wo_version"},
+							Description:   "The PostgreSQL password, as a write-only attribute that is never stored in the state. Requires Terraform 1.11 or later.",
+						},
+						"password_wo_version": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							RequiredWith: []string{"postgres_options.0.password_wo"},
+							ValidateFunc: validation.IntAtLeast(1),
+							Description:  "The version of `password_wo`. Change it to send the current value of `password_wo` to Warpgate.",
 						},
 						"tls": {
 							Type:        schema.TypeList,
@@ -319,10 +371,27 @@ func resourceTarget() *schema.Resource {
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
 									"token": {
-										Type:        schema.TypeString,
-										Required:    true,
-										Sensitive:   true,
-										Description: "The bearer token for Kubernetes authentication",
+										Type:         schema.TypeString,
+										Optional:     true,
+										Sensitive:    true,
+										ExactlyOneOf: []string{"kubernetes_options.0.token_auth.0.token", "kubernetes_options.0.token_auth.0.token_wo"},
+										Description:  "The bearer token for Kubernetes authentication",
+									},
+									"token_wo": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										WriteOnly:    true,
+										Sensitive:    true,
+										ExactlyOneOf: []string{"kubernetes_options.0.token_auth.0.token", "kubernetes_options.0.token_auth.0.token_wo"},
+										RequiredWith: []string{"kubernetes_options.0.token_auth.0.token_wo_version"},
+										Description:  "The bearer token for Kubernetes authentication, as a write-only attribute that is never stored in the state. Requires Terraform 1.11 or later.",
+									},
+									"token_wo_version": {
+										Type:         schema.TypeInt,
+										Optional:     true,
+										RequiredWith: []string{"kubernetes_options.0.token_auth.0.token_wo"},
+										ValidateFunc: validation.IntAtLeast(1),
+										Description:  "The version of `token_wo`. Change it to send the current value of `token_wo` to Warpgate.",
 									},
 								},
 							},
@@ -341,10 +410,27 @@ func resourceTarget() *schema.Resource {
 										Description: "The client certificate PEM",
 									},
 									"private_key": {
-										Type:        schema.TypeString,
-										Required:    true,
-										Sensitive:   true,
-										Description: "The client private key PEM",
+										Type:         schema.TypeString,
+										Optional:     true,
+										Sensitive:    true,
+										ExactlyOneOf: []string{"kubernetes_options.0.certificate_auth.0.private_key", "kubernetes_options.0.certificate_auth.0.private_key_wo"},
+										Description:  "The client private key PEM",
+									},
+									"private_key_wo": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										WriteOnly:    true,
+										Sensitive:    true,
+										ExactlyOneOf: []string{"kubernetes_options.0.certificate_auth.0.private_key", "kubernetes_options.0.certificate_auth.0.private_key_wo"},
+										RequiredWith: []string{"kubernetes_options.0.certificate_auth.0.private_key_wo_version"},
+										Description:  "The client private key PEM, as a write-only attribute that is never stored in the state. Requires Terraform 1.11 or later.",
+									},
+									"private_key_wo_version": {
+										Type:         schema.TypeInt,
+										Optional:     true,
+										RequiredWith: []string{"kubernetes_options.0.certificate_auth.0.private_key_wo"},
+										ValidateFunc: validation.IntAtLeast(1),
+										Description:  "The version of `private_key_wo`. Change it to send the current value of `private_key_wo` to Warpgate.",
 									},
 								},
 							},
@@ -380,6 +466,22 @@ func validateTargetConfig(ctx context.Context, d *schema.ResourceDiff, meta any)
 	return nil
 }
 
+// targetFeatureWarnings warns about target options the server may be too old
+// to support.
+func targetFeatureWarnings(d *schema.ResourceData, meta any) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if v, ok := d.GetOk("kubernetes_options"); ok && len(v.([]any)) > 0 {
+		diags = append(diags, serverFeatureWarnings(meta, featureKubernetesTargets, cty.GetAttrPath("kubernetes_options"))...)
+	}
+
+	if _, ok := d.GetOk("postgres_options.0.protocol_version"); ok {
+		diags = append(diags, serverFeatureWarnings(meta, featurePostgresProtocolVersion, cty.GetAttrPath("postgres_options").IndexInt(0).GetAttr("protocol_version"))...)
+	}
+
+	return diags
+}
+
 // resourceTargetCreate handles the creation of a new target in Warpgate based on
 // the provided resource data.
 func resourceTargetCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
@@ -515,6 +617,9 @@ func buildTargetOptions(d *schema.ResourceData) (client.TargetOptions, error) {
 	// Check for SSH options
 	if v, ok := d.GetOk("ssh_options"); ok && len(v.([]any)) > 0 {
 		sshOpts := v.([]any)[0].(map[string]any)
+		if err := readWriteOnlyAttributes(d, cty.GetAttrPath("ssh_options").IndexInt(0), sshOpts); err != nil {
+			return nil, err
+		}
 		return buildSSHTargetOptions(sshOpts)
 	}
 
@@ -527,18 +632,27 @@ func buildTargetOptions(d *schema.ResourceData) (client.TargetOptions, error) {
 	// Check for MySQL options
 	if v, ok := d.GetOk("mysql_options"); ok && len(v.([]any)) > 0 {
 		mysqlOpts := v.([]any)[0].(map[string]any)
+		if err := readWriteOnlyAttributes(d, cty.GetAttrPath("mysql_options").IndexInt(0), mysqlOpts); err != nil {
+			return nil, err
+		}
 		return buildMysqlTargetOptions(mysqlOpts)
 	}
 
 	// Check for PostgreSQL options
 	if v, ok := d.GetOk("postgres_options"); ok && len(v.([]any)) > 0 {
 		pgOpts := v.([]any)[0].(map[string]any)
+		if err := readWriteOnlyAttributes(d, cty.GetAttrPath("postgres_options").IndexInt(0), pgOpts); err != nil {
+			return nil, err
+		}
 		return buildPostgresTargetOptions(pgOpts)
 	}
 
 	// Check for Kubernetes options
 	if v, ok := d.GetOk("kubernetes_options"); ok && len(v.([]any)) > 0 {
 		k8sOpts := v.([]any)[0].(map[string]any)
+		if err := readWriteOnlyAttributes(d, cty.GetAttrPath("kubernetes_options").IndexInt(0), k8sOpts); err != nil {
+			return nil, err
+		}
 		return buildKubernetesTargetOptions(k8sOpts)
 	}
 
@@ -557,7 +671,7 @@ func buildSSHTargetOptions(opts map[string]any) (*client.TargetSSHOptions, error
 
 	if v, ok := opts["password_auth"]; ok && len(v.([]any)) > 0 {
 		pwAuth := v.([]any)[0].(map[string]any)
-		password := pwAuth["password"].(string)
+		password := secretOrWriteOnly(pwAuth, "password")
 		auth = &client.SSHTargetPasswordAuth{
 			Kind:     "Password",
 			Password: password,
@@ -625,10 +739,7 @@ func buildMysqlTargetOptions(opts map[string]any) (*client.TargetMySQLOptions, e
 	port := opts["port"].(int)
 	username := opts["username"].(string)
 
-	var password string
-	if v, ok := opts["password"]; ok {
-		password = v.(string)
-	}
+	password := secretOrWriteOnly(opts, "password")
 
 	// Extract TLS settings
 	var tls client.TLS
@@ -661,10 +772,7 @@ func buildPostgresTargetOptions(opts map[string]any) (*client.TargetPostgresOpti
 		protocolVersion = v.(string)
 	}
 
-	var password string
-	if v, ok := opts["password"]; ok {
-		password = v.(string)
-	}
+	password := secretOrWriteOnly(opts, "password")
 
 	// Extract TLS settings
 	var tls client.TLS
@@ -706,7 +814,7 @@ func buildKubernetesTargetOptions(opts map[string]any) (*client.TargetKubernetes
 
 	if v, ok := opts["token_auth"]; ok && len(v.([]any)) > 0 {
 		tokenAuth := v.([]any)[0].(map[string]any)
-		token := tokenAuth["token"].(string)
+		token := secretOrWriteOnly(tokenAuth, "token")
 		auth = &client.KubernetesTargetTokenAuth{
 			Kind:  "Token",
 			Token: token,
@@ -714,7 +822,7 @@ func buildKubernetesTargetOptions(opts map[string]any) (*client.TargetKubernetes
 	} else if v, ok := opts["certificate_auth"]; ok && len(v.([]any)) > 0 {
 		certAuth := v.([]any)[0].(map[string]any)
 		certificate := certAuth["certificate"].(string)
-		privateKey := certAuth["private_key"].(string)
+		privateKey := secretOrWriteOnly(certAuth, "private_key")
 		auth = &client.KubernetesTargetCertificateAuth{
 			Kind:        "Certificate",
 			Certificate: certificate,
@@ -732,9 +840,26 @@ func buildKubernetesTargetOptions(opts map[string]any) (*client.TargetKubernetes
 	}, nil
 }
 
+// targetWriteOnlyVersions are the version attributes of the write-only
+// variants of target secrets.
+var targetWriteOnlyVersions = []string{
+	"ssh_options.0.password_auth.0.password_wo_version",
+	"mysql_options.0.password_wo_version",
+	"postgres_options.0.password_wo_version",
+	"kubernetes_options.0.token_auth.0.token_wo_version",
+	"kubernetes_options.0.certificate_auth.0.private_key_wo_version",
+}
+
 // setTargetOptions populates the appropriate Terraform schema block based on the target type
 // from the Warpgate API.
 func setTargetOptions(d *schema.ResourceData, options any) error {
+	// The versions of write-only secrets only exist in the state and are lost
+	// when the blocks are reset, so they are carried over
+	writeOnlyVersions := make(map[string]int, len(targetWriteOnlyVersions))
+	for _, key := range targetWriteOnlyVersions {
+		writeOnlyVersions[key], _ = d.Get(key).(int)
+	}
+
 	// Reset all options blocks
 	if err := d.Set("ssh_options", []any{}); err != nil {
 		return fmt.Errorf("failed to reset ssh_options: %w", err)
@@ -789,11 +914,9 @@ func setTargetOptions(d *schema.ResourceData, options any) error {
 
 		switch authKind {
 		case "Password":
-			sshOpts["password_auth"] = []any{
-				map[string]any{
-					"password": auth["password"],
-				},
-			}
+			passwordAuth := map[string]any{}
+			setSecretAttribute(passwordAuth, "password", auth["password"], writeOnlyVersions["ssh_options.0.password_auth.0.password_wo_version"])
+			sshOpts["password_auth"] = []any{passwordAuth}
 		case "PublicKey":
 			sshOpts["public_key_auth"] = []any{
 				map[string]any{},
@@ -848,15 +971,15 @@ func setTargetOptions(d *schema.ResourceData, options any) error {
 			"tls":      []any{tlsOpts},
 		}
 
-		if password, ok := optionsMap["password"].(string); ok && password != "" {
-			mysqlOpts["password"] = password
-		} else if auth, ok := optionsMap["auth"].(map[string]any); ok {
+		password, _ := optionsMap["password"].(string)
+		if auth, ok := optionsMap["auth"].(map[string]any); ok && password == "" {
 			if kind, _ := auth["kind"].(string); kind == "Password" {
-				if pw, ok := auth["password"].(string); ok && pw != "" {
-					mysqlOpts["password"] = pw
-				}
+				password, _ = auth["password"].(string)
 			}
 		}
+		if version := writeOnlyVersions["mysql_options.0.password_wo_version"]; password != "" || version != 0 {
+			setSecretAttribute(mysqlOpts, "password", password, version)
+		}
 
 		return d.Set("mysql_options", []any{mysqlOpts})
 
@@ -882,15 +1005,15 @@ func setTargetOptions(d *schema.ResourceData, options any) error {
 			pgOpts["protocol_version"] = protocolVersion
 		}
 
-		if password, ok := optionsMap["password"].(string); ok && password != "" {
-			pgOpts["password"] = password
-		} else if auth, ok := optionsMap["auth"].(map[string]any); ok {
+		password, _ := optionsMap["password"].(string)
+		if auth, ok := optionsMap["auth"].(map[string]any); ok && password == "" {
 			if kind, _ := auth["kind"].(string); kind == "Password" {
-				if pw, ok := auth["password"].(string); ok && pw != "" {
-					pgOpts["password"] = pw
-				}
+				password, _ = auth["password"].(string)
 			}
 		}
+		if version := writeOnlyVersions["postgres_options.0.password_wo_version"]; password != "" || version != 0 {
+			setSecretAttribute(pgOpts, "password", password, version)
+		}
 
 		return d.Set("postgres_options", []any{pgOpts})
 
@@ -923,18 +1046,15 @@ func setTargetOptions(d *schema.ResourceData, options any) error {
 
 		switch authKind {
 		case "Token":
-			k8sOpts["token_auth"] = []any{
-				map[string]any{
-					"token": auth["token"],
-				},
-			}
+			tokenAuth := map[string]any{}
+			setSecretAttribute(tokenAuth, "token", auth["token"], writeOnlyVersions["kubernetes_options.0.token_auth.0.token_wo_version"])
+			k8sOpts["token_auth"] = []any{tokenAuth}
 		case "Certificate":
-			k8sOpts["certificate_auth"] = []any{
-				map[string]any{
-					"certificate": auth["certificate"],
-					"private_key": auth["private_key"],
-				},
+			certificateAuth := map[string]any{
+				"certificate": auth["certificate"],
 			}
+			setSecretAttribute(certificateAuth, "private_key", auth["private_key"], writeOnlyVersions["kubernetes_options.0.certificate_auth.0.private_key_wo_version"])
+			k8sOpts["certificate_auth"] = []any{certificateAuth}
 		default:
 			return fmt.Errorf("unknown Kubernetes auth kind: %s", authKind)
 		}
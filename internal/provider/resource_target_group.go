@@ -16,7 +16,7 @@ func resourceTargetGroup() *schema.Resource {
 		UpdateContext: resourceTargetGroupUpdate,
 		DeleteContext: resourceTargetGroupDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStateByName(lookupTargetGroupID),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
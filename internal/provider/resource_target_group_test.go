@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+)
+
+func TestAccTargetGroup(t *testing.T) {
+	name := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_target_group", testAccTargetGroupExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTargetGroupConfig(name, "Primary"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_target_group.test", testAccTargetGroupExists),
+					resource.TestCheckResourceAttr("warpgate_target_group.test", "name", name),
+					resource.TestCheckResourceAttr("warpgate_target_group.test", "color", "Primary"),
+				),
+			},
+			{
+				Config: testAccTargetGroupConfig(name, "Danger"),
+				Check:  resource.TestCheckResourceAttr("warpgate_target_group.test", "color", "Danger"),
+			},
+			{
+				ResourceName:      "warpgate_target_group.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "warpgate_target_group.test",
+				ImportState:       true,
+				ImportStateId:     "name=" + name,
+				ImportStateVerify: true,
+			},
+			{
+				Config:             testAccTargetGroupConfig(name, "Danger"),
+				Check:              testAccChangeOutOfBand("warpgate_target_group.test", testAccTargetGroupDelete),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccTargetGroupConfig(name, "Danger"),
+				Check:  testAccCheckExists("warpgate_target_group.test", testAccTargetGroupExists),
+			},
+		},
+	})
+}
+
+func testAccTargetGroupConfig(name, color string) string {
+	return fmt.Sprintf(`
+resource "warpgate_target_group" "test" {
+  name        = %q
+  description = "Test target group"
+  color       = %q
+}
+`, name, color)
+}
+
+func testAccTargetGroupExists(ctx context.Context, c *client.Client, rs *terraform.ResourceState) (bool, error) {
+	targetGroup, err := c.GetTargetGroup(ctx, rs.Primary.ID)
+	return targetGroup != nil, err
+}
+
+func testAccTargetGroupDelete(ctx context.Context, c *client.Client, rs *terraform.ResourceState) error {
+	return c.DeleteTargetGroup(ctx, rs.Primary.ID)
+}
@@ -17,7 +17,7 @@ func resourceTargetRole() *schema.Resource {
 		ReadContext:   resourceTargetRoleRead,
 		DeleteContext: resourceTargetRoleDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importJoinState("target_id", "role_id", lookupTargetID, lookupRoleID),
 		},
 		Schema: map[string]*schema.Schema{
 			"target_id": {
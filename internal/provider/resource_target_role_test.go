@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+)
+
+func TestAccTargetRole(t *testing.T) {
+	name := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_target_role", testAccTargetRoleExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTargetRoleConfig(name, "first"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_target_role.test", testAccTargetRoleExists),
+					resource.TestCheckResourceAttrPair("warpgate_target_role.test", "target_id", "warpgate_target.test", "id"),
+					resource.TestCheckResourceAttrPair("warpgate_target_role.test", "role_id", "warpgate_role.first", "id"),
+				),
+			},
+			{
+				Config: testAccTargetRoleConfig(name, "second"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_target_role.test", testAccTargetRoleExists),
+					resource.TestCheckResourceAttrPair("warpgate_target_role.test", "role_id", "warpgate_role.second", "id"),
+				),
+			},
+			{
+				ResourceName:      "warpgate_target_role.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "warpgate_target_role.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%[1]s:%[1]s-second", name),
+				ImportStateVerify: true,
+			},
+			{
+				Config:             testAccTargetRoleConfig(name, "second"),
+				Check:              testAccChangeOutOfBand("warpgate_target_role.test", testAccTargetRoleDelete),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccTargetRoleConfig(name, "second"),
+				Check:  testAccCheckExists("warpgate_target_role.test", testAccTargetRoleExists),
+			},
+		},
+	})
+}
+
+func testAccTargetRoleConfig(name, role string) string {
+	return fmt.Sprintf(`
+resource "warpgate_target" "test" {
+  name = %[1]q
+
+  http_options {
+    url = "https://internal.example.com"
+
+    tls {
+      mode   = "Disabled"
+      verify = false
+    }
+  }
+}
+
+resource "warpgate_role" "first" {
+  name = "%[1]s-first"
+}
+
+resource "warpgate_role" "second" {
+  name = "%[1]s-second"
+}
+
+resource "warpgate_target_role" "test" {
+  target_id = warpgate_target.test.id
+  role_id = warpgate_role.%[2]s.id
+}
+`, name, role)
+}
+
+func testAccTargetRoleExists(ctx context.Context, c *client.Client, rs *terraform.ResourceState) (bool, error) {
+	targetID, roleID, _ := strings.Cut(rs.Primary.ID, ":")
+	return testAccTargetHasRole(ctx, c, targetID, roleID)
+}
+
+func testAccTargetRoleDelete(ctx context.Context, c *client.Client, rs *terraform.ResourceState) error {
+	targetID, roleID, _ := strings.Cut(rs.Primary.ID, ":")
+	return c.DeleteTargetRole(ctx, targetID, roleID)
+}
+
+// testAccTargetHasRole reports whether the role is assigned to the target, treating
+// a deleted target as having no roles.
+func testAccTargetHasRole(ctx context.Context, c *client.Client, targetID, roleID string) (bool, error) {
+	target, err := c.GetTarget(ctx, targetID)
+	if err != nil || target == nil {
+		return false, err
+	}
+
+	roles, err := c.GetTargetRoles(ctx, targetID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, role := range roles {
+		if role.ID == roleID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,165 @@
+// Package provider implements the Terraform provider for Warpgate
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+)
+
+// resourceTargetRoles creates and returns a schema for the authoritative target role
+// set resource. Unlike warpgate_target_role, it owns the complete set of roles
+// allowed on a target and removes any role that is not declared.
+func resourceTargetRoles() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceTargetRolesCreate,
+		ReadContext:   resourceTargetRolesRead,
+		UpdateContext: resourceTargetRolesUpdate,
+		DeleteContext: resourceTargetRolesDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importStateByName(lookupTargetID),
+		},
+		Schema: map[string]*schema.Schema{
+			"target_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The ID of the target whose roles are managed",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"role_ids": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				Description: "The complete set of role IDs allowed on the target. Roles assigned outside of this resource are removed.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// resourceTargetRolesCreate reconciles the target's roles with the declared set.
+func resourceTargetRolesCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	targetID := d.Get("target_id").(string)
+
+	if err := reconcileTargetRoles(ctx, c, targetID, expandStringSet(d.Get("role_ids").(*schema.Set))); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(targetID)
+
+	return resourceTargetRolesRead(ctx, d, meta)
+}
+
+// resourceTargetRolesRead retrieves the roles currently allowed on the target so that
+// roles granted out-of-band are reported as drift.
+func resourceTargetRolesRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	var diags diag.Diagnostics
+
+	targetID := d.Id()
+
+	target, err := c.GetTarget(ctx, targetID)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to read target: %w", err))
+	}
+
+	// If the target was not found, the role set no longer exists either
+	if target == nil {
+		d.SetId("")
+		return diags
+	}
+
+	roles, err := c.GetTargetRoles(ctx, targetID)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to get target roles: %w", err))
+	}
+
+	if err := d.Set("target_id", targetID); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set target_id: %w", err))
+	}
+
+	if err := d.Set("role_ids", roleIDs(roles)); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set role_ids: %w", err))
+	}
+
+	return diags
+}
+
+// resourceTargetRolesUpdate reconciles the target's roles with the updated set.
+func resourceTargetRolesUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	if err := reconcileTargetRoles(ctx, c, d.Id(), expandStringSet(d.Get("role_ids").(*schema.Set))); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceTargetRolesRead(ctx, d, meta)
+}
+
+// resourceTargetRolesDelete removes every role managed by this resource from the target.
+func resourceTargetRolesDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	var diags diag.Diagnostics
+
+	targetID := d.Id()
+
+	roles, err := c.GetTargetRoles(ctx, targetID)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to get target roles: %w", err))
+	}
+
+	// Only remove roles that are still assigned, so that roles already removed
+	// out-of-band do not fail the destroy
+	managed := d.Get("role_ids").(*schema.Set)
+	for _, roleID := range roleIDs(roles) {
+		if !managed.Contains(roleID) {
+			continue
+		}
+		if err := c.DeleteTargetRole(ctx, targetID, roleID); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to remove role %s from target: %w", roleID, err))
+		}
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// reconcileTargetRoles adds and removes role assignments so that the target ends up
+// with exactly the desired set of roles.
+func reconcileTargetRoles(ctx context.Context, c *client.Client, targetID string, desired []string) error {
+	roles, err := c.GetTargetRoles(ctx, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to get target roles: %w", err)
+	}
+
+	toAdd, toRemove := diffRoleIDs(roleIDs(roles), desired)
+
+	for _, roleID := range toAdd {
+		if err := c.AddTargetRole(ctx, targetID, roleID); err != nil {
+			return fmt.Errorf("failed to assign role %s to target: %w", roleID, err)
+		}
+	}
+
+	for _, roleID := range toRemove {
+		if err := c.DeleteTargetRole(ctx, targetID, roleID); err != nil {
+			return fmt.Errorf("failed to remove role %s from target: %w", roleID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+)
+
+func TestAccTargetRoles(t *testing.T) {
+	name := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_target_roles", testAccTargetRolesExist),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTargetRolesConfig(name, "warpgate_role.first.id"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_target_roles.test", testAccTargetRolesExist),
+					resource.TestCheckResourceAttr("warpgate_target_roles.test", "role_ids.#", "1"),
+				),
+			},
+			{
+				Config: testAccTargetRolesConfig(name, "warpgate_role.first.id", "warpgate_role.second.id"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_target_roles.test", testAccTargetRolesExist),
+					resource.TestCheckResourceAttr("warpgate_target_roles.test", "role_ids.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "warpgate_target_roles.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "warpgate_target_roles.test",
+				ImportState:       true,
+				ImportStateId:     "name=" + name,
+				ImportStateVerify: true,
+			},
+			{
+				// A role granted outside of Terraform must show up as drift
+				Config:             testAccTargetRolesConfig(name, "warpgate_role.first.id", "warpgate_role.second.id"),
+				Check:              testAccChangeOutOfBand("warpgate_target_roles.test", testAccTargetRolesGrant(name+"-extra")),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccTargetRolesConfig(name, "warpgate_role.first.id", "warpgate_role.second.id"),
+				Check:  resource.TestCheckResourceAttr("warpgate_target_roles.test", "role_ids.#", "2"),
+			},
+		},
+	})
+}
+
+func testAccTargetRolesConfig(name string, roleIDs ...string) string {
+	return fmt.Sprintf(`
+resource "warpgate_target" "test" {
+  name = %[1]q
+
+  http_options {
+    url = "https://internal.example.com"
+
+    tls {
+      mode   = "Disabled"
+      verify = false
+    }
+  }
+}
+
+resource "warpgate_role" "first" {
+  name = "%[1]s-first"
+}
+
+resource "warpgate_role" "second" {
+  name = "%[1]s-second"
+}
+
+resource "warpgate_role" "extra" {
+  name = "%[1]s-extra"
+}
+
+resource "warpgate_target_roles" "test" {
+  target_id = warpgate_target.test.id
+  role_ids  = [%[2]s]
+}
+`, name, strings.Join(roleIDs, ", "))
+}
+
+// testAccTargetRolesExist reports whether any of the managed roles is still assigned.
+func testAccTargetRolesExist(ctx context.Context, c *client.Client, rs *terraform.ResourceState) (bool, error) {
+	for key, roleID := range rs.Primary.Attributes {
+		if !strings.HasPrefix(key, "role_ids.") || key == "role_ids.#" {
+			continue
+		}
+
+		assigned, err := testAccTargetHasRole(ctx, c, rs.Primary.ID, roleID)
+		if err != nil || assigned {
+			return assigned, err
+		}
+	}
+
+	return false, nil
+}
+
+// testAccTargetRolesGrant returns a change that assigns the named role to the target
+// behind Terraform's back.
+func testAccTargetRolesGrant(roleName string) func(context.Context, *client.Client, *terraform.ResourceState) error {
+	return func(ctx context.Context, c *client.Client, rs *terraform.ResourceState) error {
+		roleID, err := lookupRoleID(ctx, c, roleName)
+		if err != nil {
+			return err
+		}
+
+		return c.AddTargetRole(ctx, rs.Primary.ID, roleID)
+	}
+}
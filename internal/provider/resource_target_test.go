@@ -1,9 +1,14 @@
 package provider
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
 )
 
@@ -58,3 +63,249 @@ func TestSetTargetOptionsWithPostgresProtocolVersion(t *testing.T) {
 		t.Fatalf("expected protocol version 3.2, got %v", got)
 	}
 }
+
+func TestAccTarget(t *testing.T) {
+	name := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_target", testAccTargetExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTargetSSHConfig(name, 22),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_target.test", testAccTargetExists),
+					resource.TestCheckResourceAttr("warpgate_target.test", "name", name),
+					resource.TestCheckResourceAttr("warpgate_target.test", "ssh_options.0.host", "10.0.0.1"),
+					resource.TestCheckResourceAttr("warpgate_target.test", "ssh_options.0.port", "22"),
+					resource.TestCheckResourceAttrPair("warpgate_target.test", "group_id", "warpgate_target_group.test", "id"),
+				),
+			},
+			{
+				Config: testAccTargetSSHConfig(name, 2222),
+				Check:  resource.TestCheckResourceAttr("warpgate_target.test", "ssh_options.0.port", "2222"),
+			},
+			{
+				Config: testAccTargetHTTPConfig(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("warpgate_target.test", "ssh_options.#", "0"),
+					resource.TestCheckResourceAttr("warpgate_target.test", "http_options.0.url", "https://internal.example.com"),
+					resource.TestCheckResourceAttr("warpgate_target.test", "http_options.0.tls.0.mode", "Required"),
+				),
+			},
+			{
+				ResourceName:      "warpgate_target.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "warpgate_target.test",
+				ImportState:       true,
+				ImportStateId:     "name=" + name,
+				ImportStateVerify: true,
+			},
+			{
+				Config:             testAccTargetHTTPConfig(name),
+				Check:              testAccChangeOutOfBand("warpgate_target.test", testAccTargetDelete),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccTargetHTTPConfig(name),
+				Check:  testAccCheckExists("warpgate_target.test", testAccTargetExists),
+			},
+		},
+	})
+}
+
+func testAccTargetSSHConfig(name string, port int) string {
+	return fmt.Sprintf(`
+resource "warpgate_target_group" "test" {
+  name = %[1]q
+}
+
+resource "warpgate_target" "test" {
+  name        = %[1]q
+  description = "Test SSH target"
+  group_id    = warpgate_target_group.test.id
+
+  ssh_options {
+    host     = "10.0.0.1"
+    port     = %[2]d
+    username = "root"
+
+    password_auth {
+      password = "secret"
+    }
+  }
+}
+`, name, port)
+}
+
+func testAccTargetHTTPConfig(name string) string {
+	return fmt.Sprintf(`
+resource "warpgate_target_group" "test" {
+  name = %[1]q
+}
+
+resource "warpgate_target" "test" {
+  name        = %[1]q
+  description = "Test HTTP target"
+  group_id    = warpgate_target_group.test.id
+
+  http_options {
+    url = "https://internal.example.com"
+
+    tls {
+      mode   = "Required"
+      verify = true
+    }
+  }
+}
+`, name)
+}
+
+func TestAccTarget_writeOnly(t *testing.T) {
+	name := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_target", testAccTargetExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTargetMySQLWriteOnlyConfig(name, "first-password", 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("warpgate_target.test", "mysql_options.0.password", ""),
+					resource.TestCheckNoResourceAttr("warpgate_target.test", "mysql_options.0.password_wo"),
+					resource.TestCheckResourceAttr("warpgate_target.test", "mysql_options.0.password_wo_version", "1"),
+					testAccCheckTargetPassword("warpgate_target.test", "first-password"),
+				),
+			},
+			{
+				// Without a version bump, a new value is not sent
+				Config: testAccTargetMySQLWriteOnlyConfig(name, "second-password", 1),
+				Check:  testAccCheckTargetPassword("warpgate_target.test", "first-password"),
+			},
+			{
+				Config: testAccTargetMySQLWriteOnlyConfig(name, "second-password", 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("warpgate_target.test", "mysql_options.0.password", ""),
+					resource.TestCheckResourceAttr("warpgate_target.test", "mysql_options.0.password_wo_version", "2"),
+					testAccCheckTargetPassword("warpgate_target.test", "second-password"),
+				),
+			},
+		},
+	})
+}
+
+func testAccTargetMySQLWriteOnlyConfig(name, password string, version int) string {
+	return fmt.Sprintf(`
+resource "warpgate_target" "test" {
+  name = %q
+
+  mysql_options {
+    host                = "db.example.com"
+    port                = 3306
+    username            = "admin"
+    password_wo         = %q
+    password_wo_version = %d
+
+    tls {
+      mode   = "Preferred"
+      verify = false
+    }
+  }
+}
+`, name, password, version)
+}
+
+// testAccCheckTargetPassword returns a check verifying the password that
+// Warpgate has stored for the named database target.
+func testAccCheckTargetPassword(name, password string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", name)
+		}
+
+		c, err := testAccClient()
+		if err != nil {
+			return err
+		}
+
+		target, err := c.GetTarget(context.Background(), rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return fmt.Errorf("%s does not exist", name)
+		}
+
+		options, err := targetOptionsToMap(target.Options)
+		if err != nil {
+			return err
+		}
+
+		if got := options["password"]; got != password {
+			return fmt.Errorf("expected password %q, got %q", password, got)
+		}
+
+		return nil
+	}
+}
+
+func testAccTargetExists(ctx context.Context, c *client.Client, rs *terraform.ResourceState) (bool, error) {
+	target, err := c.GetTarget(ctx, rs.Primary.ID)
+	return target != nil, err
+}
+
+func testAccTargetDelete(ctx context.Context, c *client.Client, rs *terraform.ResourceState) error {
+	return c.DeleteTarget(ctx, rs.Primary.ID)
+}
+
+// TestSetTargetOptionsFromFixture runs the kind dispatch of setTargetOptions on
+// the target payloads in the client fixture. The fixture is hand-written after
+// the Warpgate API schema, so this checks the dispatch against that schema, not
+// against payloads of a real server.
+func TestSetTargetOptionsFromFixture(t *testing.T) {
+	fixture, err := client.LoadFixture("../client/testdata/targets.json")
+	if err != nil {
+		t.Fatalf("LoadFixture returned error: %v", err)
+	}
+
+	c, err := client.NewClient(&client.Config{
+		Host:      client.AdminAPIURL("https://warpgate.example.com"),
+		Transport: client.NewReplayTransport(fixture),
+	})
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	targets, err := c.GetTargets(context.Background(), "")
+	if err != nil {
+		t.Fatalf("GetTargets returned error: %v", err)
+	}
+
+	blocks := map[string]string{
+		"Ssh":        "ssh_options",
+		"Http":       "http_options",
+		"MySql":      "mysql_options",
+		"Postgres":   "postgres_options",
+		"Kubernetes": "kubernetes_options",
+	}
+
+	for _, target := range targets {
+		kind := target.Options.(map[string]any)["kind"].(string)
+		block, ok := blocks[kind]
+		if !ok {
+			// Built-in targets such as WebAdmin can't be managed by the provider
+			continue
+		}
+
+		d := schema.TestResourceDataRaw(t, resourceTarget().Schema, map[string]any{})
+		if err := setTargetOptions(d, target.Options); err != nil {
+			t.Fatalf("setTargetOptions returned error for target %s: %v", target.Name, err)
+		}
+
+		if got := len(d.Get(block).([]any)); got != 1 {
+			t.Fatalf("expected one %s block for target %s, got %d", block, target.Name, got)
+		}
+	}
+}
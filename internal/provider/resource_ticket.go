@@ -95,13 +95,45 @@ func resourceTicketCreate(ctx context.Context, d *schema.ResourceData, meta any)
 }
 
 // resourceTicketRead retrieves the ticket data from Warpgate and updates the
-// Terraform state accordingly.
+// Terraform state accordingly. Warpgate has no endpoint for a single ticket,
+// so the ticket is looked up in the list of all tickets. Tickets that were
+// deleted, for example after their last use, are removed from the state.
 func resourceTicketRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	// TODO: We do not refresh from warpgate yet as warpgate does not yet support a GET ticket endpoint.
-	// So for now we just use the existing state information and don't refresh it.
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
 
 	var diags diag.Diagnostics
 
+	tickets, err := c.GetTickets(ctx)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to list tickets: %w", err))
+	}
+
+	var ticket *client.Ticket
+	for i := range tickets {
+		if tickets[i].ID == d.Id() {
+			ticket = &tickets[i]
+			break
+		}
+	}
+
+	if ticket == nil {
+		d.SetId("")
+		return diags
+	}
+
+	if err := d.Set("username", ticket.Username); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set username: %w", err))
+	}
+
+	if err := d.Set("target_name", ticket.Target); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set target_name: %w", err))
+	}
+
+	if err := d.Set("description", ticket.Description); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set description: %w", err))
+	}
+
 	return diags
 }
 
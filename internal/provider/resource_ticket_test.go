@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+)
+
+func TestAccTicket(t *testing.T) {
+	name := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_ticket", testAccTicketExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTicketConfig(name, "First ticket"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_ticket.test", testAccTicketExists),
+					resource.TestCheckResourceAttr("warpgate_ticket.test", "username", name),
+					resource.TestCheckResourceAttr("warpgate_ticket.test", "target_name", name),
+					resource.TestCheckResourceAttrSet("warpgate_ticket.test", "secret"),
+				),
+			},
+			{
+				// Every change replaces the ticket
+				Config: testAccTicketConfig(name, "Second ticket"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_ticket.test", testAccTicketExists),
+					resource.TestCheckResourceAttr("warpgate_ticket.test", "description", "Second ticket"),
+				),
+			},
+			{
+				// The secret is only returned on creation, and the number of
+				// uses isn't returned at all
+				ResourceName:            "warpgate_ticket.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"secret", "number_of_uses"},
+			},
+			{
+				// A deleted ticket, for example after its last use, is recreated
+				Config:             testAccTicketConfig(name, "Second ticket"),
+				Check:              testAccChangeOutOfBand("warpgate_ticket.test", testAccTicketDelete),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccTicketConfig(name, "Second ticket"),
+				Check:  testAccCheckExists("warpgate_ticket.test", testAccTicketExists),
+			},
+		},
+	})
+}
+
+func testAccTicketConfig(name, description string) string {
+	return fmt.Sprintf(`
+resource "warpgate_user" "test" {
+  username = %[1]q
+}
+
+resource "warpgate_target" "test" {
+  name = %[1]q
+
+  http_options {
+    url = "https://internal.example.com"
+
+    tls {
+      mode   = "Disabled"
+      verify = false
+    }
+  }
+}
+
+resource "warpgate_ticket" "test" {
+  username       = warpgate_user.test.username
+  target_name    = warpgate_target.test.name
+  number_of_uses = 3
+  description    = %[2]q
+}
+`, name, description)
+}
+
+func testAccTicketExists(ctx context.Context, c *client.Client, rs *terraform.ResourceState) (bool, error) {
+	tickets, err := c.GetTickets(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for _, ticket := range tickets {
+		if ticket.ID == rs.Primary.ID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func testAccTicketDelete(ctx context.Context, c *client.Client, rs *terraform.ResourceState) error {
+	return c.DeleteTicket(ctx, rs.Primary.ID)
+}
@@ -4,7 +4,10 @@ package provider
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -14,12 +17,12 @@ import (
 // resourceUser creates and returns a schema for the user resource.
 func resourceUser() *schema.Resource {
 	return &schema.Resource{
-		CreateContext: resourceUserCreate,
+		CreateContext: withFeatureWarnings(resourceUserCreate, userFeatureWarnings),
 		ReadContext:   resourceUserRead,
-		UpdateContext: resourceUserUpdate,
+		UpdateContext: withFeatureWarnings(resourceUserUpdate, userFeatureWarnings),
 		DeleteContext: resourceUserDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStateByName(lookupUserID),
 		},
 		Schema: map[string]*schema.Schema{
 			"username": {
@@ -40,50 +43,152 @@ func resourceUser() *schema.Resource {
 				Description: "The credential policy for the user",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
-						"http": {
-							Type:     schema.TypeList,
-							Optional: true,
-							Elem: &schema.Schema{
-								Type: schema.TypeString,
-							},
-						},
-						"ssh": {
-							Type:     schema.TypeList,
-							Optional: true,
-							Elem: &schema.Schema{
-								Type: schema.TypeString,
-							},
-						},
-						"mysql": {
-							Type:     schema.TypeList,
-							Optional: true,
-							Elem: &schema.Schema{
-								Type: schema.TypeString,
-							},
-						},
-						"postgres": {
-							Type:     schema.TypeList,
-							Optional: true,
-							Elem: &schema.Schema{
-								Type: schema.TypeString,
-							},
-						},
+						"http":       credentialKindListSchema("http"),
+						"ssh":        credentialKindListSchema("ssh"),
+						"mysql":      credentialKindListSchema("mysql"),
+						"postgres":   credentialKindListSchema("postgres"),
+						"kubernetes": credentialKindListSchema("kubernetes"),
 					},
 				},
 			},
 			"allowed_ip_ranges": {
-				Type:        schema.TypeList,
-				Optional:    true,
-				Description: "List of allowed IP ranges in CIDR notation. If set, only connections from these IP ranges will be allowed for this user.",
+				Type:             schema.TypeList,
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentList(canonicalIPRange),
+				Description:      "List of allowed IP ranges in CIDR notation or as bare IP addresses, in any order. If set, only connections from these IP ranges will be allowed for this user. Equivalent notations such as `10.0.0.1` and `10.0.0.1/32` don't cause a diff.",
 				Elem: &schema.Schema{
-					Type: schema.TypeString,
+					Type:         schema.TypeString,
+					ValidateFunc: validateIPRange,
 				},
 			},
 		},
+		ValidateRawResourceConfigFuncs: []schema.ValidateRawResourceConfigFunc{
+			warnAllowedIPRanges,
+		},
 		CustomizeDiff: validateUserConfig,
 	}
 }
 
+// credentialKinds lists every credential kind known to Warpgate.
+var credentialKinds = []client.CredentialKind{
+	client.CredentialKindPassword,
+	client.CredentialKindPublicKey,
+	client.CredentialKindCertificate,
+	client.CredentialKindTotp,
+	client.CredentialKindSso,
+	client.CredentialKindWebUserApproval,
+}
+
+// credentialPolicyKinds lists the credential kinds Warpgate accepts in the
+// credential policy of each protocol. SSO logins happen in the browser, so SSH
+// and database clients use web user approval instead. Kubernetes clients can't
+// authenticate interactively, so Warpgate only accepts client certificates for
+// them.
+var credentialPolicyKinds = map[string][]client.CredentialKind{
+	"http": {
+		client.CredentialKindPassword,
+		client.CredentialKindTotp,
+		client.CredentialKindSso,
+	},
+	"ssh": {
+		client.CredentialKindPassword,
+		client.CredentialKindPublicKey,
+		client.CredentialKindTotp,
+		client.CredentialKindWebUserApproval,
+	},
+	"mysql": {
+		client.CredentialKindPassword,
+	},
+	"postgres": {
+		client.CredentialKindPassword,
+		client.CredentialKindWebUserApproval,
+	},
+	"kubernetes": {
+		client.CredentialKindCertificate,
+	},
+}
+
+// credentialPolicyProtocols maps the keys of the credential_policy block to the
+// names of their protocols.
+var credentialPolicyProtocols = map[string]string{
+	"http":       "HTTP",
+	"ssh":        "SSH",
+	"mysql":      "MySQL",
+	"postgres":   "PostgreSQL",
+	"kubernetes": "Kubernetes",
+}
+
+// credentialKindListSchema returns the schema of the credential_policy list of
+// a protocol. The list is validated against the known credential kinds and
+// compared as a set, since Warpgate requires all listed kinds regardless of
+// their order.
+func credentialKindListSchema(key string) *schema.Schema {
+	kinds := make([]string, len(credentialPolicyKinds[key]))
+	for i, kind := range credentialPolicyKinds[key] {
+		kinds[i] = fmt.Sprintf("`%s`", kind)
+	}
+
+	return &schema.Schema{
+		Type:             schema.TypeList,
+		Optional:         true,
+		DiffSuppressFunc: suppressEquivalentList(identity),
+		Description:      fmt.Sprintf("The credential kinds required for %s access, in any order. Valid values: %s.", credentialPolicyProtocols[key], strings.Join(kinds, ", ")),
+		Elem: &schema.Schema{
+			Type:         schema.TypeString,
+			ValidateFunc: validation.StringInSlice(credentialKindStrings(credentialKinds), false),
+		},
+	}
+}
+
+// credentialKindStrings converts credential kinds to strings.
+func credentialKindStrings(kinds []client.CredentialKind) []string {
+	result := make([]string, len(kinds))
+	for i, kind := range kinds {
+		result[i] = string(kind)
+	}
+	return result
+}
+
+// suppressEquivalentList returns a DiffSuppressFunc for lists that are
+// compared as sets of their normalized values, so that reordering, duplicating
+// or renotating entries doesn't cause a diff. It is called with the keys of the
+// list's elements and length, so it compares the whole list.
+func suppressEquivalentList(normalize func(string) string) schema.SchemaDiffSuppressFunc {
+	return func(k, oldValue, newValue string, d *schema.ResourceData) bool {
+		listKey := k[:strings.LastIndex(k, ".")]
+		o, n := d.GetChange(listKey)
+
+		oldSet := normalizedSet(o.([]any), normalize)
+		newSet := normalizedSet(n.([]any), normalize)
+		if len(oldSet) != len(newSet) {
+			return false
+		}
+
+		for v := range newSet {
+			if !oldSet[v] {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// normalizedSet returns the set of normalized values of a string list.
+func normalizedSet(list []any, normalize func(string) string) map[string]bool {
+	set := make(map[string]bool, len(list))
+	for _, v := range list {
+		s, _ := v.(string)
+		set[normalize(s)] = true
+	}
+	return set
+}
+
+// identity returns its argument, for lists compared without normalization.
+func identity(s string) string {
+	return s
+}
+
 // resourceUserCreate handles the creation of a new user in Warpgate based on
 // the provided resource data.
 func resourceUserCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
@@ -131,15 +236,43 @@ func resourceUserCreate(ctx context.Context, d *schema.ResourceData, meta any) d
 		}
 	}
 
-	return resourceUserRead(ctx, d, meta)
+	return readUser(ctx, d, meta)
 }
 
-// resourceUserRead retrieves the user data from Warpgate and updates the
-// Terraform state accordingly.
+// resourceUserRead refreshes the user and warns about credential policy entries
+// the user has no credentials for, or when the credentials can't be looked up.
+// The check is left out after a create or update, since the credentials
+// usually depend on the user and are only added afterwards. It only covers
+// the policy in state: a planned policy can't be checked, as CustomizeDiff
+// can't report warnings.
 func resourceUserRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	providerMeta := meta.(*providerMeta)
 	c := providerMeta.client
 
+	diags := readUser(ctx, d, meta)
+	if diags.HasError() || d.Id() == "" {
+		return diags
+	}
+
+	var policy *client.UserRequireCredentialsPolicy
+	if v, ok := d.GetOk("credential_policy"); ok {
+		policy = expandCredentialPolicy(v.([]any))
+	}
+
+	unsatisfied, err := unsatisfiedCredentialPolicy(ctx, c, d.Id(), policy)
+	if err != nil {
+		return append(diags, credentialPolicyCheckWarning(d.Get("username").(string), err))
+	}
+
+	return append(diags, unsatisfiedCredentialPolicyWarnings(d.Get("username").(string), unsatisfied)...)
+}
+
+// readUser retrieves the user data from Warpgate and updates the Terraform
+// state accordingly.
+func readUser(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
 	var diags diag.Diagnostics
 
 	id := d.Id()
@@ -205,7 +338,7 @@ func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, meta any) d
 		return diag.FromErr(fmt.Errorf("failed to update user: %w", err))
 	}
 
-	return resourceUserRead(ctx, d, meta)
+	return readUser(ctx, d, meta)
 }
 
 // resourceUserDelete removes a user from Warpgate based on the resource data.
@@ -253,6 +386,10 @@ func expandCredentialPolicy(policyList []any) *client.UserRequireCredentialsPoli
 		policy.Postgres = expandCredentialKindList(v.([]any))
 	}
 
+	if v, ok := policyMap["kubernetes"]; ok && v != nil {
+		policy.Kubernetes = expandCredentialKindList(v.([]any))
+	}
+
 	return policy
 }
 
@@ -295,6 +432,10 @@ func flattenCredentialPolicy(policy *client.UserRequireCredentialsPolicy) []any
 		result["postgres"] = flattenCredentialKindList(policy.Postgres)
 	}
 
+	if policy.Kubernetes != nil {
+		result["kubernetes"] = flattenCredentialKindList(policy.Kubernetes)
+	}
+
 	return []any{result}
 }
 
@@ -312,26 +453,152 @@ func flattenCredentialKindList(list []client.CredentialKind) []any {
 	return result
 }
 
-// expandAllowedIPRanges converts the allowed_ip_ranges from Terraform schema to the API format.
+// expandAllowedIPRanges converts the allowed_ip_ranges from Terraform schema to
+// the API format. Ranges are sent in canonical CIDR notation, without
+// duplicates.
 func expandAllowedIPRanges(d *schema.ResourceData) *[]string {
 	v, ok := d.GetOk("allowed_ip_ranges")
 	if !ok || v == nil {
 		return nil
 	}
 	raw := v.([]any)
-	ranges := make([]string, len(raw))
-	for i, r := range raw {
-		ranges[i] = r.(string)
+	ranges := make([]string, 0, len(raw))
+	for _, r := range raw {
+		ipRange := canonicalIPRange(r.(string))
+		if !slices.Contains(ranges, ipRange) {
+			ranges = append(ranges, ipRange)
+		}
 	}
 	return &ranges
 }
 
+// unsatisfiedCredentialPolicy returns, per credential_policy key, the credential
+// kinds the policy requires but the user has no credentials of. Web user
+// approval needs no credential and is always satisfied.
+func unsatisfiedCredentialPolicy(ctx context.Context, c *client.Client, userID string, policy *client.UserRequireCredentialsPolicy) (map[string][]client.CredentialKind, error) {
+	if policy == nil {
+		return nil, nil
+	}
+
+	protocols := map[string][]client.CredentialKind{
+		"http":       policy.HTTP,
+		"ssh":        policy.SSH,
+		"mysql":      policy.MySQL,
+		"postgres":   policy.Postgres,
+		"kubernetes": policy.Kubernetes,
+	}
+
+	// Credentials are only looked up for the kinds in use, and once per kind
+	hasCredentials := make(map[client.CredentialKind]bool)
+	unsatisfied := make(map[string][]client.CredentialKind)
+	for key, kinds := range protocols {
+		for _, kind := range kinds {
+			has, ok := hasCredentials[kind]
+			if !ok {
+				var err error
+				if has, err = userHasCredentials(ctx, c, userID, kind); err != nil {
+					return nil, err
+				}
+				hasCredentials[kind] = has
+			}
+
+			if !has {
+				unsatisfied[key] = append(unsatisfied[key], kind)
+			}
+		}
+	}
+
+	return unsatisfied, nil
+}
+
+// userHasCredentials reports whether a user has any credential of the given
+// kind.
+func userHasCredentials(ctx context.Context, c *client.Client, userID string, kind client.CredentialKind) (bool, error) {
+	var count int
+	var err error
+	switch kind {
+	case client.CredentialKindPassword:
+		var creds []client.PasswordCredential
+		creds, err = c.GetPasswordCredentials(ctx, userID)
+		count = len(creds)
+	case client.CredentialKindPublicKey:
+		var creds []client.PublicKeyCredential
+		creds, err = c.GetPublicKeyCredentials(ctx, userID)
+		count = len(creds)
+	case client.CredentialKindCertificate:
+		var creds []client.CertificateCredential
+		creds, err = c.GetCertificateCredentials(ctx, userID)
+		count = len(creds)
+	case client.CredentialKindTotp:
+		var creds []client.OtpCredential
+		creds, err = c.GetOtpCredentials(ctx, userID)
+		count = len(creds)
+	case client.CredentialKindSso:
+		var creds []client.SsoCredential
+		creds, err = c.GetSsoCredentials(ctx, userID)
+		count = len(creds)
+	default:
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get %s credentials: %w", kind, err)
+	}
+
+	return count > 0, nil
+}
+
+// credentialPolicyCheckWarning returns the warning reported when the
+// credentials of a user couldn't be looked up to check its credential policy.
+// The check is advisory, so it must not fail a read.
+func credentialPolicyCheckWarning(username string, err error) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Failed to check the credential policy of user %s", username),
+		Detail:   err.Error(),
+	}
+}
+
+// unsatisfiedCredentialPolicyWarnings returns a warning for each protocol the
+// user can't log in with because of missing credentials.
+func unsatisfiedCredentialPolicyWarnings(username string, unsatisfied map[string][]client.CredentialKind) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	keys := make([]string, 0, len(unsatisfied))
+	for key := range unsatisfied {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("User %s can't log in over %s", username, credentialPolicyProtocols[key]),
+			Detail: fmt.Sprintf("The credential policy requires %s for %s, but the user has no such credentials. Add them, or the user is locked out of %s.",
+				strings.Join(credentialKindStrings(unsatisfied[key]), ", "), key, credentialPolicyProtocols[key]),
+			AttributePath: cty.GetAttrPath("credential_policy").IndexInt(0).GetAttr(key),
+		})
+	}
+
+	return diags
+}
+
+// userFeatureWarnings warns about credential policy entries the server may be
+// too old to support.
+func userFeatureWarnings(d *schema.ResourceData, meta any) diag.Diagnostics {
+	if v, ok := d.GetOk("credential_policy.0.kubernetes"); ok && len(v.([]any)) > 0 {
+		return serverFeatureWarnings(meta, featureKubernetesPolicy, cty.GetAttrPath("credential_policy").IndexInt(0).GetAttr("kubernetes"))
+	}
+
+	return nil
+}
+
 // validateUserConfig validates the user configuration in a Terraform resource diff,
-// ensuring that credential policies are correctly formatted.
+// ensuring that credential policies only use credential kinds Warpgate accepts
+// for each protocol.
 func validateUserConfig(ctx context.Context, d *schema.ResourceDiff, meta any) error {
 	if v, ok := d.GetOk("credential_policy"); ok {
 		credPolicies, ok := v.([]any)
-		if !ok || len(credPolicies) == 0 {
+		if !ok || len(credPolicies) == 0 || credPolicies[0] == nil {
 			return nil
 		}
 
@@ -340,19 +607,10 @@ func validateUserConfig(ctx context.Context, d *schema.ResourceDiff, meta any) e
 			return fmt.Errorf("credential_policy must be a map")
 		}
 
-		// Valid credential kinds
-		validKinds := map[string]bool{
-			"Password":        true,
-			"PublicKey":       true,
-			"Totp":            true,
-			"Sso":             true,
-			"WebUserApproval": true,
-		}
-
 		// Validate each field
 		for key, val := range policy {
-			// Validate only for known keys
-			if key != "http" && key != "ssh" && key != "mysql" && key != "postgres" {
+			validKinds, ok := credentialPolicyKinds[key]
+			if !ok {
 				return fmt.Errorf("unknown credential policy key: %s", key)
 			}
 
@@ -362,12 +620,24 @@ func validateUserConfig(ctx context.Context, d *schema.ResourceDiff, meta any) e
 				return fmt.Errorf("credential_policy.%s must be a list", key)
 			}
 
-			// Validate each credential kind in the list
+			// Validate each credential kind in the list. Kinds that are unknown
+			// at plan time are checked once they are known.
+			seen := make(map[string]bool, len(valueList))
 			for i, kind := range valueList {
 				kindStr, ok := kind.(string)
-				if !ok || !validKinds[kindStr] {
-					return fmt.Errorf("credential_policy.%s[%d]: %s is not a valid credential kind", key, i, kindStr)
+				if !ok || kindStr == "" {
+					continue
+				}
+
+				if !slices.Contains(validKinds, client.CredentialKind(kindStr)) {
+					return fmt.Errorf("credential_policy.%s[%d]: Warpgate does not accept %s for %s, valid kinds are %s",
+						key, i, kindStr, key, strings.Join(credentialKindStrings(validKinds), ", "))
+				}
+
+				if seen[kindStr] {
+					return fmt.Errorf("credential_policy.%s[%d]: %s is listed more than once", key, i, kindStr)
 				}
+				seen[kindStr] = true
 			}
 		}
 	}
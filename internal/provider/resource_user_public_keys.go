@@ -0,0 +1,446 @@
+// Package provider implements the Terraform provider for Warpgate
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+	"golang.org/x/crypto/ssh"
+)
+
+// resourceUserPublicKeys creates and returns a schema for the authoritative
+// public key resource. Unlike warpgate_public_key_credential, it owns the
+// complete set of public keys of a user and deletes any key that is not
+// declared.
+func resourceUserPublicKeys() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceUserPublicKeysCreate,
+		ReadContext:   resourceUserPublicKeysRead,
+		UpdateContext: resourceUserPublicKeysUpdate,
+		DeleteContext: resourceUserPublicKeysDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importStateByName(lookupUserID),
+		},
+		Schema: map[string]*schema.Schema{
+			"user_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The ID of the user whose public keys are managed",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"authorized_keys": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ExactlyOneOf:     []string{"authorized_keys", "key"},
+				ValidateFunc:     validateAuthorizedKeys,
+				DiffSuppressFunc: suppressEquivalentAuthorizedKeys,
+				Description:      "The complete set of public keys of the user, in OpenSSH authorized_keys format with one key per line. The comment of each key is used as its label, or its fingerprint if it has none. Empty lines and lines starting with `#` are ignored.",
+			},
+			"key": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				ExactlyOneOf: []string{"authorized_keys", "key"},
+				Set:          hashDeclaredPublicKey,
+				Description:  "The complete set of public keys of the user, with their labels",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"label": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+							Description:  "A label for the public key",
+						},
+						"public_key": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateFunc:     validatePublicKey,
+							DiffSuppressFunc: suppressEquivalentPublicKey,
+							Description:      "The public key, in any format accepted by `warpgate_public_key_credential`",
+						},
+					},
+				},
+			},
+		},
+		CustomizeDiff: checkDuplicatePublicKeys,
+	}
+}
+
+// declaredPublicKey is a public key declared for a user, in normalized
+// authorized_keys format without comment.
+type declaredPublicKey struct {
+	Label     string
+	PublicKey string
+}
+
+// parseAuthorizedKeys parses a multi-line authorized_keys value. The comment of
+// each key is used as its label, falling back to its fingerprint.
+func parseAuthorizedKeys(s string) ([]declaredPublicKey, error) {
+	var keys []declaredPublicKey
+	lines := map[string]int{}
+
+	for i, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, comment, options, rest, err := ssh.ParseAuthorizedKey([]byte(line))
+		if err != nil {
+			return nil, fmt.Errorf("line %d is not a valid OpenSSH public key: %w", i+1, err)
+		}
+		if len(options) > 0 {
+			return nil, fmt.Errorf("line %d must not have authorized_keys options", i+1)
+		}
+		if len(bytes.TrimSpace(rest)) > 0 {
+			return nil, fmt.Errorf("line %d must contain a single public key", i+1)
+		}
+		if _, ok := key.(*ssh.Certificate); ok {
+			return nil, fmt.Errorf("line %d must be a public key, not an SSH certificate", i+1)
+		}
+		if err := checkPublicKeyStrength(key); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		publicKey := string(bytes.TrimSpace(ssh.MarshalAuthorizedKey(key)))
+		if first, ok := lines[publicKey]; ok {
+			return nil, fmt.Errorf("line %d repeats the key on line %d", i+1, first)
+		}
+		lines[publicKey] = i + 1
+
+		label := comment
+		if label == "" {
+			label = ssh.FingerprintSHA256(key)
+		}
+
+		keys = append(keys, declaredPublicKey{Label: label, PublicKey: publicKey})
+	}
+
+	return keys, nil
+}
+
+// validateAuthorizedKeys checks that a value is a valid authorized_keys value.
+func validateAuthorizedKeys(v any, k string) ([]string, []error) {
+	if _, err := parseAuthorizedKeys(v.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	return nil, nil
+}
+
+// canonicalAuthorizedKeys returns an authorized_keys value with its keys
+// normalized and sorted, and comments and empty lines dropped. Values that
+// don't parse are returned as-is.
+func canonicalAuthorizedKeys(s string) string {
+	keys, err := parseAuthorizedKeys(s)
+	if err != nil {
+		return s
+	}
+	return formatAuthorizedKeys(keys)
+}
+
+// formatAuthorizedKeys renders keys as a sorted authorized_keys value, with
+// their labels as comments.
+func formatAuthorizedKeys(keys []declaredPublicKey) string {
+	lines := make([]string, len(keys))
+	for i, key := range keys {
+		lines[i] = strings.TrimSpace(key.PublicKey + " " + key.Label)
+	}
+	sort.Strings(lines)
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// suppressEquivalentAuthorizedKeys suppresses diffs between authorized_keys
+// values declaring the same keys with the same labels, in any order and
+// notation.
+func suppressEquivalentAuthorizedKeys(k, oldValue, newValue string, d *schema.ResourceData) bool {
+	return canonicalAuthorizedKeys(oldValue) == canonicalAuthorizedKeys(newValue)
+}
+
+// hashDeclaredPublicKey hashes key blocks by their normalized public key and
+// label, so that notations of the same key are the same set element.
+func hashDeclaredPublicKey(v any) int {
+	m := v.(map[string]any)
+	label, _ := m["label"].(string)
+	publicKey, _ := m["public_key"].(string)
+	return schema.HashString(normalizePublicKey(publicKey) + "\n" + label)
+}
+
+// expandDeclaredPublicKeys converts the key blocks to declared public keys.
+func expandDeclaredPublicKeys(set *schema.Set) []declaredPublicKey {
+	keys := make([]declaredPublicKey, 0, set.Len())
+	for _, v := range set.List() {
+		m := v.(map[string]any)
+		keys = append(keys, declaredPublicKey{
+			Label:     m["label"].(string),
+			PublicKey: normalizePublicKey(m["public_key"].(string)),
+		})
+	}
+	return keys
+}
+
+// expandUserPublicKeys returns the public keys declared in either
+// authorized_keys or the key blocks.
+func expandUserPublicKeys(d *schema.ResourceData) ([]declaredPublicKey, error) {
+	if v, ok := d.GetOk("authorized_keys"); ok {
+		return parseAuthorizedKeys(v.(string))
+	}
+	return expandDeclaredPublicKeys(d.Get("key").(*schema.Set)), nil
+}
+
+// checkDuplicatePublicKeys rejects key blocks declaring the same public key
+// more than once, as Warpgate would register it twice.
+func checkDuplicatePublicKeys(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	seen := map[string]string{}
+	for _, v := range d.Get("key").(*schema.Set).List() {
+		m := v.(map[string]any)
+		label, _ := m["label"].(string)
+		publicKey, _ := m["public_key"].(string)
+
+		// Unknown and invalid keys are reported elsewhere
+		if publicKey == "" {
+			continue
+		}
+		if _, err := parsePublicKey(publicKey); err != nil {
+			continue
+		}
+
+		publicKey = normalizePublicKey(publicKey)
+		if other, ok := seen[publicKey]; ok {
+			return fmt.Errorf("the public key of %q is also declared as %q", label, other)
+		}
+		seen[publicKey] = label
+	}
+
+	return nil
+}
+
+// resourceUserPublicKeysCreate reconciles the user's public keys with the
+// declared set.
+func resourceUserPublicKeysCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	userID := d.Get("user_id").(string)
+
+	keys, err := expandUserPublicKeys(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := reconcileUserPublicKeys(ctx, c, userID, keys); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(userID)
+
+	return resourceUserPublicKeysRead(ctx, d, meta)
+}
+
+// resourceUserPublicKeysRead retrieves the public keys of the user so that keys
+// added, relabeled or deleted out-of-band are reported as drift.
+func resourceUserPublicKeysRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	var diags diag.Diagnostics
+
+	userID := d.Id()
+
+	user, err := c.GetUser(ctx, userID)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to read user: %w", err))
+	}
+
+	// If the user was not found, the keys no longer exist either
+	if user == nil {
+		d.SetId("")
+		return diags
+	}
+
+	creds, err := c.GetPublicKeyCredentials(ctx, userID)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to get public key credentials: %w", err))
+	}
+
+	if err := d.Set("user_id", userID); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set user_id: %w", err))
+	}
+
+	keys := make([]declaredPublicKey, len(creds))
+	for i, cred := range creds {
+		keys[i] = declaredPublicKey{Label: cred.Label, PublicKey: normalizePublicKey(cred.OpensshPublicKey)}
+	}
+
+	// The configured notation is kept as long as it declares the same keys.
+	// Imported keys are recorded as key blocks.
+	if current, ok := d.GetOk("authorized_keys"); ok {
+		if canonicalAuthorizedKeys(current.(string)) != formatAuthorizedKeys(keys) {
+			if err := d.Set("authorized_keys", formatAuthorizedKeys(keys)); err != nil {
+				return diag.FromErr(fmt.Errorf("failed to set authorized_keys: %w", err))
+			}
+		}
+		return diags
+	}
+
+	if err := d.Set("key", flattenDeclaredPublicKeys(keys)); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set key: %w", err))
+	}
+
+	return diags
+}
+
+// resourceUserPublicKeysUpdate reconciles the user's public keys with the
+// updated set.
+func resourceUserPublicKeysUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	keys, err := expandUserPublicKeys(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := reconcileUserPublicKeys(ctx, c, d.Id(), keys); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceUserPublicKeysRead(ctx, d, meta)
+}
+
+// resourceUserPublicKeysDelete deletes every public key managed by this
+// resource from the user.
+func resourceUserPublicKeysDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	var diags diag.Diagnostics
+
+	userID := d.Id()
+
+	keys, err := expandUserPublicKeys(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	managed := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		managed[key.PublicKey] = true
+	}
+
+	creds, err := c.GetPublicKeyCredentials(ctx, userID)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to get public key credentials: %w", err))
+	}
+
+	// Only delete keys that still exist, so that keys already deleted
+	// out-of-band do not fail the destroy
+	for _, cred := range creds {
+		if !managed[normalizePublicKey(cred.OpensshPublicKey)] {
+			continue
+		}
+		if err := c.DeletePublicKeyCredential(ctx, userID, cred.ID); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to delete public key %s: %w", cred.Label, err))
+		}
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// reconcileUserPublicKeys adds, relabels and deletes public key credentials so
+// that the user ends up with exactly the desired keys. New keys are added
+// before others are deleted, so that the user is never left without a key
+// while keys are being replaced.
+func reconcileUserPublicKeys(ctx context.Context, c *client.Client, userID string, desired []declaredPublicKey) error {
+	creds, err := c.GetPublicKeyCredentials(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get public key credentials: %w", err)
+	}
+
+	toAdd, toRelabel, toDelete := diffPublicKeys(creds, desired)
+
+	for _, key := range toAdd {
+		if _, err := c.AddPublicKeyCredential(ctx, userID, key.Label, key.PublicKey); err != nil {
+			return fmt.Errorf("failed to add public key %s: %w", key.Label, err)
+		}
+	}
+
+	for _, cred := range toRelabel {
+		if _, err := c.UpdatePublicKeyCredential(ctx, userID, cred.ID, cred.Label, cred.OpensshPublicKey); err != nil {
+			return fmt.Errorf("failed to relabel public key %s: %w", cred.Label, err)
+		}
+	}
+
+	for _, cred := range toDelete {
+		if err := c.DeletePublicKeyCredential(ctx, userID, cred.ID); err != nil {
+			return fmt.Errorf("failed to delete public key %s: %w", cred.Label, err)
+		}
+	}
+
+	return nil
+}
+
+// diffPublicKeys compares the current credentials and the desired keys by
+// public key. It returns the keys to add, the credentials to relabel with their
+// new label, and the credentials to delete, including duplicates of a key.
+// Keys to add are sorted by label for a deterministic API call order.
+func diffPublicKeys(current []client.PublicKeyCredential, desired []declaredPublicKey) ([]declaredPublicKey, []client.PublicKeyCredential, []client.PublicKeyCredential) {
+	labels := make(map[string]string, len(desired))
+	for _, key := range desired {
+		labels[key.PublicKey] = key.Label
+	}
+
+	var toRelabel, toDelete []client.PublicKeyCredential
+	existing := make(map[string]bool, len(current))
+	for _, cred := range current {
+		publicKey := normalizePublicKey(cred.OpensshPublicKey)
+
+		label, ok := labels[publicKey]
+		if !ok || existing[publicKey] {
+			toDelete = append(toDelete, cred)
+			continue
+		}
+		existing[publicKey] = true
+
+		if cred.Label != label {
+			cred.Label = label
+			cred.OpensshPublicKey = publicKey
+			toRelabel = append(toRelabel, cred)
+		}
+	}
+
+	var toAdd []declaredPublicKey
+	for _, key := range desired {
+		if !existing[key.PublicKey] {
+			toAdd = append(toAdd, key)
+			existing[key.PublicKey] = true
+		}
+	}
+	sort.Slice(toAdd, func(i, j int) bool { return toAdd[i].Label < toAdd[j].Label })
+
+	return toAdd, toRelabel, toDelete
+}
+
+// flattenDeclaredPublicKeys converts public keys to key blocks.
+func flattenDeclaredPublicKeys(keys []declaredPublicKey) []any {
+	result := make([]any, len(keys))
+	for i, key := range keys {
+		result[i] = map[string]any{
+			"label":      key.Label,
+			"public_key": key.PublicKey,
+		}
+	}
+	return result
+}
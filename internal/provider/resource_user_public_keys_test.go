@@ -0,0 +1,229 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestParseAuthorizedKeys(t *testing.T) {
+	keys, err := parseAuthorizedKeys("# Laptops\n" + testAccPublicKey + "  alice@laptop \n\n" + testAccOtherPublicKey + "\n")
+	if err != nil {
+		t.Fatalf("parseAuthorizedKeys returned error: %v", err)
+	}
+
+	want := []declaredPublicKey{
+		{Label: "alice@laptop", PublicKey: testAccPublicKey},
+		{Label: "SHA256:vN3DxZyqCYhqsgWvWYZLOKIvSKuIbsPltPj1Jo4BOpc", PublicKey: testAccOtherPublicKey},
+	}
+	if !reflect.DeepEqual(keys, want) {
+		t.Fatalf("expected %v, got %v", want, keys)
+	}
+
+	for _, tc := range []struct {
+		value    string
+		expected string
+	}{
+		{testAccPublicKey + "\nnot a key", "line 2 is not a valid OpenSSH public key"},
+		{`command="ls" ` + testAccPublicKey, "line 1 must not have authorized_keys options"},
+		{testAccPublicKey + " a\n\n" + testAccPublicKey + " b", "line 3 repeats the key on line 1"},
+		{strings.TrimSpace(string(ssh.MarshalAuthorizedKey(testRSAPublicKey(t, 1024)))), "line 1: RSA keys must have at least 2048 bits"},
+	} {
+		_, err := parseAuthorizedKeys(tc.value)
+		if err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("parseAuthorizedKeys(%q) returned error %v, expected it to contain %q", tc.value, err, tc.expected)
+		}
+	}
+}
+
+func TestDiffPublicKeys(t *testing.T) {
+	current := []client.PublicKeyCredential{
+		{ID: "1", Label: "laptop", OpensshPublicKey: testAccPublicKey},
+		{ID: "2", Label: "old", OpensshPublicKey: testAccOtherPublicKey},
+		{ID: "3", Label: "copy", OpensshPublicKey: testAccPublicKey},
+	}
+	desired := []declaredPublicKey{
+		{Label: "laptop", PublicKey: testAccPublicKey},
+		{Label: "workstation", PublicKey: testAccOtherPublicKey},
+		{Label: "new", PublicKey: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFakeWarpgateHostKeyForTestsOnly0000000000"},
+	}
+
+	toAdd, toRelabel, toDelete := diffPublicKeys(current, desired)
+
+	if len(toAdd) != 1 || toAdd[0].Label != "new" {
+		t.Errorf("expected to add the new key, got %v", toAdd)
+	}
+	if len(toRelabel) != 1 || toRelabel[0].ID != "2" || toRelabel[0].Label != "workstation" {
+		t.Errorf("expected to relabel credential 2 to workstation, got %v", toRelabel)
+	}
+	if len(toDelete) != 1 || toDelete[0].ID != "3" {
+		t.Errorf("expected to delete the duplicate credential 3, got %v", toDelete)
+	}
+}
+
+func TestAccUserPublicKeys(t *testing.T) {
+	username := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_user_public_keys", testAccUserPublicKeysExist),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccUserPublicKeysConfig(username, testAccPublicKey+" laptop\n"+testAccPublicKey+" copy\n"),
+				ExpectError: regexp.MustCompile(`line 2 repeats the key on line 1`),
+			},
+			{
+				Config: testAccUserPublicKeysConfig(username, testAccPublicKey+" laptop\n"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_user_public_keys.test", testAccUserPublicKeysExist),
+					testAccCheckUserPublicKeyLabels(username, "laptop"),
+				),
+			},
+			{
+				// Keys are added and relabeled in place
+				Config: testAccUserPublicKeysConfig(username, "# Alice\n"+testAccPublicKey+" alice@laptop\n"+testAccOtherPublicKey+" alice@workstation\n"),
+				Check:  testAccCheckUserPublicKeyLabels(username, "alice@laptop", "alice@workstation"),
+			},
+			{
+				// Reordering keys and comment lines doesn't cause a diff
+				Config:   testAccUserPublicKeysConfig(username, testAccOtherPublicKey+" alice@workstation\n\n"+testAccPublicKey+"   alice@laptop\n"),
+				PlanOnly: true,
+			},
+			{
+				// A key added outside of Terraform must show up as drift
+				Config:             testAccUserPublicKeysConfig(username, testAccPublicKey+" alice@laptop\n"+testAccOtherPublicKey+" alice@workstation\n"),
+				Check:              testAccChangeOutOfBand("warpgate_user_public_keys.test", testAccUserPublicKeysAdd(t)),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccUserPublicKeysConfig(username, testAccOtherPublicKey+" alice@workstation\n"),
+				Check:  testAccCheckUserPublicKeyLabels(username, "alice@workstation"),
+			},
+		},
+	})
+}
+
+func TestAccUserPublicKeys_blocks(t *testing.T) {
+	username := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_user_public_keys", testAccUserPublicKeysExist),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserPublicKeysBlocksConfig(username, testAccPublicKey+" alice@laptop"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("warpgate_user_public_keys.test", "key.#", "2"),
+					testAccCheckUserPublicKeyLabels(username, "laptop", "workstation"),
+				),
+			},
+			{
+				// The comment isn't stored and doesn't cause a diff
+				Config:   testAccUserPublicKeysBlocksConfig(username, testAccPublicKey),
+				PlanOnly: true,
+			},
+			{
+				ResourceName:      "warpgate_user_public_keys.test",
+				ImportState:       true,
+				ImportStateId:     "name=" + username,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccUserPublicKeysConfig(username, authorizedKeys string) string {
+	return fmt.Sprintf(`
+resource "warpgate_user" "test" {
+  username = %q
+}
+
+resource "warpgate_user_public_keys" "test" {
+  user_id         = warpgate_user.test.id
+  authorized_keys = %q
+}
+`, username, authorizedKeys)
+}
+
+func testAccUserPublicKeysBlocksConfig(username, laptopKey string) string {
+	return fmt.Sprintf(`
+resource "warpgate_user" "test" {
+  username = %q
+}
+
+resource "warpgate_user_public_keys" "test" {
+  user_id = warpgate_user.test.id
+
+  key {
+    label      = "laptop"
+    public_key = %q
+  }
+
+  key {
+    label      = "workstation"
+    public_key = %q
+  }
+}
+`, username, laptopKey, testAccOtherPublicKey)
+}
+
+// testAccUserPublicKeysExist reports whether the user still has any public key.
+func testAccUserPublicKeysExist(ctx context.Context, c *client.Client, rs *terraform.ResourceState) (bool, error) {
+	user, err := c.GetUser(ctx, rs.Primary.ID)
+	if err != nil || user == nil {
+		return false, err
+	}
+
+	creds, err := c.GetPublicKeyCredentials(ctx, rs.Primary.ID)
+	return len(creds) > 0, err
+}
+
+// testAccUserPublicKeysAdd returns a change that adds a public key to the user
+// behind Terraform's back.
+func testAccUserPublicKeysAdd(t *testing.T) func(context.Context, *client.Client, *terraform.ResourceState) error {
+	publicKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(testRSAPublicKey(t, 2048))))
+
+	return func(ctx context.Context, c *client.Client, rs *terraform.ResourceState) error {
+		_, err := c.AddPublicKeyCredential(ctx, rs.Primary.ID, "out-of-band", publicKey)
+		return err
+	}
+}
+
+// testAccCheckUserPublicKeyLabels returns a check verifying the labels of the
+// user's public keys.
+func testAccCheckUserPublicKeyLabels(username string, labels ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		c, err := testAccClient()
+		if err != nil {
+			return err
+		}
+
+		userID, err := lookupUserID(context.Background(), c, username)
+		if err != nil {
+			return err
+		}
+
+		creds, err := c.GetPublicKeyCredentials(context.Background(), userID)
+		if err != nil {
+			return err
+		}
+
+		var got []string
+		for _, cred := range creds {
+			got = append(got, cred.Label)
+		}
+
+		if strings.Join(got, ",") != strings.Join(labels, ",") {
+			return fmt.Errorf("expected public keys %v, got %v", labels, got)
+		}
+
+		return nil
+	}
+}
@@ -17,7 +17,7 @@ func resourceUserRole() *schema.Resource {
 		ReadContext:   resourceUserRoleRead,
 		DeleteContext: resourceUserRoleDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importJoinState("user_id", "role_id", lookupUserID, lookupRoleID),
 		},
 		Schema: map[string]*schema.Schema{
 			"user_id": {
@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+)
+
+func TestAccUserRole(t *testing.T) {
+	name := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_user_role", testAccUserRoleExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserRoleConfig(name, "first"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_user_role.test", testAccUserRoleExists),
+					resource.TestCheckResourceAttrPair("warpgate_user_role.test", "user_id", "warpgate_user.test", "id"),
+					resource.TestCheckResourceAttrPair("warpgate_user_role.test", "role_id", "warpgate_role.first", "id"),
+				),
+			},
+			{
+				Config: testAccUserRoleConfig(name, "second"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_user_role.test", testAccUserRoleExists),
+					resource.TestCheckResourceAttrPair("warpgate_user_role.test", "role_id", "warpgate_role.second", "id"),
+				),
+			},
+			{
+				ResourceName:      "warpgate_user_role.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "warpgate_user_role.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%[1]s:%[1]s-second", name),
+				ImportStateVerify: true,
+			},
+			{
+				Config:             testAccUserRoleConfig(name, "second"),
+				Check:              testAccChangeOutOfBand("warpgate_user_role.test", testAccUserRoleDelete),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccUserRoleConfig(name, "second"),
+				Check:  testAccCheckExists("warpgate_user_role.test", testAccUserRoleExists),
+			},
+		},
+	})
+}
+
+func testAccUserRoleConfig(name, role string) string {
+	return fmt.Sprintf(`
+resource "warpgate_user" "test" {
+  username = %[1]q
+}
+
+resource "warpgate_role" "first" {
+  name = "%[1]s-first"
+}
+
+resource "warpgate_role" "second" {
+  name = "%[1]s-second"
+}
+
+resource "warpgate_user_role" "test" {
+  user_id = warpgate_user.test.id
+  role_id = warpgate_role.%[2]s.id
+}
+`, name, role)
+}
+
+func testAccUserRoleExists(ctx context.Context, c *client.Client, rs *terraform.ResourceState) (bool, error) {
+	userID, roleID, _ := strings.Cut(rs.Primary.ID, ":")
+	return testAccUserHasRole(ctx, c, userID, roleID)
+}
+
+func testAccUserRoleDelete(ctx context.Context, c *client.Client, rs *terraform.ResourceState) error {
+	userID, roleID, _ := strings.Cut(rs.Primary.ID, ":")
+	return c.DeleteUserRole(ctx, userID, roleID)
+}
+
+// testAccUserHasRole reports whether the role is assigned to the user, treating
+// a deleted user as having no roles.
+func testAccUserHasRole(ctx context.Context, c *client.Client, userID, roleID string) (bool, error) {
+	user, err := c.GetUser(ctx, userID)
+	if err != nil || user == nil {
+		return false, err
+	}
+
+	roles, err := c.GetUserRoles(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, role := range roles {
+		if role.ID == roleID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
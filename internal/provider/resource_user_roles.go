@@ -0,0 +1,220 @@
+// Package provider implements the Terraform provider for Warpgate
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+)
+
+// resourceUserRoles creates and returns a schema for the authoritative user role
+// membership resource. Unlike warpgate_user_role, it owns the complete set of
+// roles assigned to a user and removes any role that is not declared.
+func resourceUserRoles() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceUserRolesCreate,
+		ReadContext:   resourceUserRolesRead,
+		UpdateContext: resourceUserRolesUpdate,
+		DeleteContext: resourceUserRolesDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importStateByName(lookupUserID),
+		},
+		Schema: map[string]*schema.Schema{
+			"user_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The ID of the user whose roles are managed",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"role_ids": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				Description: "The complete set of role IDs assigned to the user. Roles assigned outside of this resource are removed.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// resourceUserRolesCreate reconciles the user's roles with the declared set.
+func resourceUserRolesCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	userID := d.Get("user_id").(string)
+
+	if err := reconcileUserRoles(ctx, c, userID, expandStringSet(d.Get("role_ids").(*schema.Set))); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(userID)
+
+	return resourceUserRolesRead(ctx, d, meta)
+}
+
+// resourceUserRolesRead retrieves the roles currently assigned to the user so that
+// roles granted out-of-band are reported as drift.
+func resourceUserRolesRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	var diags diag.Diagnostics
+
+	userID := d.Id()
+
+	user, err := c.GetUser(ctx, userID)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to read user: %w", err))
+	}
+
+	// If the user was not found, the membership no longer exists either
+	if user == nil {
+		d.SetId("")
+		return diags
+	}
+
+	roles, err := c.GetUserRoles(ctx, userID)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to get user roles: %w", err))
+	}
+
+	if err := d.Set("user_id", userID); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set user_id: %w", err))
+	}
+
+	if err := d.Set("role_ids", roleIDs(roles)); err != nil {
+		return diag.FromErr(fmt.Errorf("failed to set role_ids: %w", err))
+	}
+
+	return diags
+}
+
+// resourceUserRolesUpdate reconciles the user's roles with the updated set.
+func resourceUserRolesUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	if err := reconcileUserRoles(ctx, c, d.Id(), expandStringSet(d.Get("role_ids").(*schema.Set))); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceUserRolesRead(ctx, d, meta)
+}
+
+// resourceUserRolesDelete removes every role managed by this resource from the user.
+func resourceUserRolesDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
+	var diags diag.Diagnostics
+
+	userID := d.Id()
+
+	roles, err := c.GetUserRoles(ctx, userID)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to get user roles: %w", err))
+	}
+
+	// Only remove roles that are still assigned, so that roles already removed
+	// out-of-band do not fail the destroy
+	managed := d.Get("role_ids").(*schema.Set)
+	for _, roleID := range roleIDs(roles) {
+		if !managed.Contains(roleID) {
+			continue
+		}
+		if err := c.DeleteUserRole(ctx, userID, roleID); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to remove role %s from user: %w", roleID, err))
+		}
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// reconcileUserRoles adds and removes role assignments so that the user ends up
+// with exactly the desired set of roles.
+func reconcileUserRoles(ctx context.Context, c *client.Client, userID string, desired []string) error {
+	roles, err := c.GetUserRoles(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user roles: %w", err)
+	}
+
+	toAdd, toRemove := diffRoleIDs(roleIDs(roles), desired)
+
+	for _, roleID := range toAdd {
+		if err := c.AddUserRole(ctx, userID, roleID); err != nil {
+			return fmt.Errorf("failed to assign role %s to user: %w", roleID, err)
+		}
+	}
+
+	for _, roleID := range toRemove {
+		if err := c.DeleteUserRole(ctx, userID, roleID); err != nil {
+			return fmt.Errorf("failed to remove role %s from user: %w", roleID, err)
+		}
+	}
+
+	return nil
+}
+
+// diffRoleIDs compares the current and desired role IDs and returns the IDs that
+// have to be added and removed, both sorted for deterministic API call order.
+func diffRoleIDs(current, desired []string) ([]string, []string) {
+	currentSet := make(map[string]bool, len(current))
+	for _, id := range current {
+		currentSet[id] = true
+	}
+
+	desiredSet := make(map[string]bool, len(desired))
+	for _, id := range desired {
+		desiredSet[id] = true
+	}
+
+	var toAdd, toRemove []string
+	for id := range desiredSet {
+		if !currentSet[id] {
+			toAdd = append(toAdd, id)
+		}
+	}
+	for id := range currentSet {
+		if !desiredSet[id] {
+			toRemove = append(toRemove, id)
+		}
+	}
+
+	sort.Strings(toAdd)
+	sort.Strings(toRemove)
+
+	return toAdd, toRemove
+}
+
+// roleIDs extracts the IDs from a list of roles.
+func roleIDs(roles []client.Role) []string {
+	ids := make([]string, len(roles))
+	for i, role := range roles {
+		ids[i] = role.ID
+	}
+	return ids
+}
+
+// expandStringSet converts a Terraform set of strings to a string slice.
+func expandStringSet(set *schema.Set) []string {
+	if set == nil {
+		return nil
+	}
+
+	list := set.List()
+	result := make([]string, len(list))
+	for i, v := range list {
+		result[i] = v.(string)
+	}
+	return result
+}
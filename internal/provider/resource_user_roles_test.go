@@ -0,0 +1,140 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+)
+
+func TestDiffRoleIDs(t *testing.T) {
+	toAdd, toRemove := diffRoleIDs(
+		[]string{"admins", "developers", "out-of-band"},
+		[]string{"developers", "readers", "admins", "readers"},
+	)
+
+	if want := []string{"readers"}; !reflect.DeepEqual(toAdd, want) {
+		t.Fatalf("expected roles to add %v, got %v", want, toAdd)
+	}
+
+	if want := []string{"out-of-band"}; !reflect.DeepEqual(toRemove, want) {
+		t.Fatalf("expected roles to remove %v, got %v", want, toRemove)
+	}
+}
+
+func TestDiffRoleIDsRemovesAllWhenNothingDesired(t *testing.T) {
+	toAdd, toRemove := diffRoleIDs([]string{"b", "a"}, nil)
+
+	if len(toAdd) != 0 {
+		t.Fatalf("expected no roles to add, got %v", toAdd)
+	}
+
+	if want := []string{"a", "b"}; !reflect.DeepEqual(toRemove, want) {
+		t.Fatalf("expected roles to remove %v, got %v", want, toRemove)
+	}
+}
+
+func TestAccUserRoles(t *testing.T) {
+	name := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_user_roles", testAccUserRolesExist),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserRolesConfig(name, "warpgate_role.first.id"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_user_roles.test", testAccUserRolesExist),
+					resource.TestCheckResourceAttr("warpgate_user_roles.test", "role_ids.#", "1"),
+				),
+			},
+			{
+				Config: testAccUserRolesConfig(name, "warpgate_role.first.id", "warpgate_role.second.id"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_user_roles.test", testAccUserRolesExist),
+					resource.TestCheckResourceAttr("warpgate_user_roles.test", "role_ids.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "warpgate_user_roles.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "warpgate_user_roles.test",
+				ImportState:       true,
+				ImportStateId:     "name=" + name,
+				ImportStateVerify: true,
+			},
+			{
+				// A role granted outside of Terraform must show up as drift
+				Config:             testAccUserRolesConfig(name, "warpgate_role.first.id", "warpgate_role.second.id"),
+				Check:              testAccChangeOutOfBand("warpgate_user_roles.test", testAccUserRolesGrant(name+"-extra")),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccUserRolesConfig(name, "warpgate_role.first.id", "warpgate_role.second.id"),
+				Check:  resource.TestCheckResourceAttr("warpgate_user_roles.test", "role_ids.#", "2"),
+			},
+		},
+	})
+}
+
+func testAccUserRolesConfig(name string, roleIDs ...string) string {
+	return fmt.Sprintf(`
+resource "warpgate_user" "test" {
+  username = %[1]q
+}
+
+resource "warpgate_role" "first" {
+  name = "%[1]s-first"
+}
+
+resource "warpgate_role" "second" {
+  name = "%[1]s-second"
+}
+
+resource "warpgate_role" "extra" {
+  name = "%[1]s-extra"
+}
+
+resource "warpgate_user_roles" "test" {
+  user_id  = warpgate_user.test.id
+  role_ids = [%[2]s]
+}
+`, name, strings.Join(roleIDs, ", "))
+}
+
+// testAccUserRolesExist reports whether any of the managed roles is still assigned.
+func testAccUserRolesExist(ctx context.Context, c *client.Client, rs *terraform.ResourceState) (bool, error) {
+	for key, roleID := range rs.Primary.Attributes {
+		if !strings.HasPrefix(key, "role_ids.") || key == "role_ids.#" {
+			continue
+		}
+
+		assigned, err := testAccUserHasRole(ctx, c, rs.Primary.ID, roleID)
+		if err != nil || assigned {
+			return assigned, err
+		}
+	}
+
+	return false, nil
+}
+
+// testAccUserRolesGrant returns a change that assigns the named role to the user
+// behind Terraform's back.
+func testAccUserRolesGrant(roleName string) func(context.Context, *client.Client, *terraform.ResourceState) error {
+	return func(ctx context.Context, c *client.Client, rs *terraform.ResourceState) error {
+		roleID, err := lookupRoleID(ctx, c, roleName)
+		if err != nil {
+			return err
+		}
+
+		return c.AddUserRole(ctx, rs.Primary.ID, roleID)
+	}
+}
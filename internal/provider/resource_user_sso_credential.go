@@ -154,13 +154,23 @@ func resourceUserSsoCredentialDelete(ctx context.Context, d *schema.ResourceData
 }
 
 // resourceUserSsoCredentialImport handles the import of an existing SSO credential.
-// The import ID should be in the format "user_id:credential_id".
+// The import ID should be in the format "user_id:credential_id", where the user ID
+// may be replaced by the username.
 func resourceUserSsoCredentialImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	providerMeta := meta.(*providerMeta)
+	c := providerMeta.client
+
 	userID, credentialID, err := parseCompositeID(d.Id(), "user_id", "credential_id")
 	if err != nil {
 		return nil, err
 	}
 
+	if !isUUID(userID) {
+		if userID, err = lookupUserID(ctx, c, userID); err != nil {
+			return nil, err
+		}
+	}
+
 	d.SetId(credentialID)
 	if err := d.Set("user_id", userID); err != nil {
 		return nil, fmt.Errorf("failed to set user_id: %w", err)
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+)
+
+func TestAccUserSsoCredential(t *testing.T) {
+	username := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_user_sso_credential", testAccUserSsoCredentialExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserSsoCredentialConfig(username, "alice@example.com"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_user_sso_credential.test", testAccUserSsoCredentialExists),
+					resource.TestCheckResourceAttr("warpgate_user_sso_credential.test", "sso_provider", "google"),
+					resource.TestCheckResourceAttr("warpgate_user_sso_credential.test", "email", "alice@example.com"),
+				),
+			},
+			{
+				Config: testAccUserSsoCredentialConfig(username, "alice@example.org"),
+				Check:  resource.TestCheckResourceAttr("warpgate_user_sso_credential.test", "email", "alice@example.org"),
+			},
+			{
+				ResourceName:      "warpgate_user_sso_credential.test",
+				ImportState:       true,
+				ImportStateIdFunc: testAccImportStateIDFunc("warpgate_user_sso_credential.test", "user_id", "id"),
+				ImportStateVerify: true,
+			},
+			{
+				Config:             testAccUserSsoCredentialConfig(username, "alice@example.org"),
+				Check:              testAccChangeOutOfBand("warpgate_user_sso_credential.test", testAccUserSsoCredentialDelete),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccUserSsoCredentialConfig(username, "alice@example.org"),
+				Check:  testAccCheckExists("warpgate_user_sso_credential.test", testAccUserSsoCredentialExists),
+			},
+		},
+	})
+}
+
+func testAccUserSsoCredentialConfig(username, email string) string {
+	return fmt.Sprintf(`
+resource "warpgate_user" "test" {
+  username = %q
+}
+
+resource "warpgate_user_sso_credential" "test" {
+  user_id      = warpgate_user.test.id
+  sso_provider = "google"
+  email        = %q
+}
+`, username, email)
+}
+
+func testAccUserSsoCredentialExists(ctx context.Context, c *client.Client, rs *terraform.ResourceState) (bool, error) {
+	userID := rs.Primary.Attributes["user_id"]
+
+	user, err := c.GetUser(ctx, userID)
+	if err != nil || user == nil {
+		return false, err
+	}
+
+	creds, err := c.GetSsoCredentials(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, cred := range creds {
+		if cred.ID == rs.Primary.ID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func testAccUserSsoCredentialDelete(ctx context.Context, c *client.Client, rs *terraform.ResourceState) error {
+	return c.DeleteSsoCredential(ctx, rs.Primary.Attributes["user_id"], rs.Primary.ID)
+}
@@ -0,0 +1,339 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+)
+
+func TestAccUser(t *testing.T) {
+	username := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_user", testAccUserExists),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "warpgate_user" "test" {
+  username    = %q
+  description = "Test user"
+}
+`, username),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExists("warpgate_user.test", testAccUserExists),
+					resource.TestCheckResourceAttrSet("warpgate_user.test", "id"),
+					resource.TestCheckResourceAttr("warpgate_user.test", "username", username),
+					resource.TestCheckResourceAttr("warpgate_user.test", "description", "Test user"),
+				),
+			},
+			{
+				Config: testAccUserConfigWithPolicy(username),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("warpgate_user.test", "description", "Updated"),
+					resource.TestCheckResourceAttr("warpgate_user.test", "credential_policy.0.ssh.#", "1"),
+					resource.TestCheckResourceAttr("warpgate_user.test", "credential_policy.0.ssh.0", "PublicKey"),
+					resource.TestCheckResourceAttr("warpgate_user.test", "allowed_ip_ranges.#", "1"),
+					resource.TestCheckResourceAttr("warpgate_user.test", "allowed_ip_ranges.0", "10.0.0.0/8"),
+				),
+			},
+			{
+				ResourceName:      "warpgate_user.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "warpgate_user.test",
+				ImportState:       true,
+				ImportStateId:     "name=" + username,
+				ImportStateVerify: true,
+			},
+			{
+				Config:             testAccUserConfigWithPolicy(username),
+				Check:              testAccChangeOutOfBand("warpgate_user.test", testAccUserDelete),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccUserConfigWithPolicy(username),
+				Check:  testAccCheckExists("warpgate_user.test", testAccUserExists),
+			},
+		},
+	})
+}
+
+func TestAccUser_kubernetesPolicy(t *testing.T) {
+	username := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_user", testAccUserExists),
+		Steps: []resource.TestStep{
+			{
+				// Kubernetes clients can't use interactive credentials
+				Config:      testAccUserConfigWithKubernetesPolicy(username, "Password"),
+				ExpectError: regexp.MustCompile(`credential_policy\.kubernetes\[0\]: Warpgate does not accept Password for\s+kubernetes`),
+			},
+			{
+				Config: testAccUserConfigWithKubernetesPolicy(username, "Certificate"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("warpgate_user.test", "credential_policy.0.kubernetes.#", "1"),
+					resource.TestCheckResourceAttr("warpgate_user.test", "credential_policy.0.kubernetes.0", "Certificate"),
+				),
+			},
+			{
+				ResourceName:      "warpgate_user.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccUser_credentialPolicy(t *testing.T) {
+	username := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_user", testAccUserExists),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccUserConfigWithSSHPolicy(username, "Publickey"),
+				ExpectError: regexp.MustCompile(`expected credential_policy\.0\.ssh\.0 to be one of`),
+			},
+			{
+				// SSO logins only happen in the browser
+				Config:      testAccUserConfigWithSSHPolicy(username, "Sso"),
+				ExpectError: regexp.MustCompile(`credential_policy\.ssh\[0\]: Warpgate does not accept Sso for ssh`),
+			},
+			{
+				Config:      testAccUserConfigWithSSHPolicy(username, "Password", "Password"),
+				ExpectError: regexp.MustCompile(`credential_policy\.ssh\[1\]: Password is listed more than once`),
+			},
+			{
+				Config: testAccUserConfigWithSSHPolicy(username, "Password", "Totp"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("warpgate_user.test", "credential_policy.0.ssh.#", "2"),
+					resource.TestCheckResourceAttr("warpgate_user.test", "credential_policy.0.ssh.0", "Password"),
+					resource.TestCheckResourceAttr("warpgate_user.test", "credential_policy.0.ssh.1", "Totp"),
+				),
+			},
+			{
+				// Reordering the credential kinds doesn't cause a diff
+				Config:   testAccUserConfigWithSSHPolicy(username, "Totp", "Password"),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccUserConfigWithSSHPolicy(username, "Totp", "PublicKey"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("warpgate_user.test", "credential_policy.0.ssh.#", "2"),
+					resource.TestCheckResourceAttr("warpgate_user.test", "credential_policy.0.ssh.0", "Totp"),
+					resource.TestCheckResourceAttr("warpgate_user.test", "credential_policy.0.ssh.1", "PublicKey"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_allowedIPRanges(t *testing.T) {
+	username := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_user", testAccUserExists),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccUserConfigWithAllowedIPRanges(username, "10.0.0.300"),
+				ExpectError: regexp.MustCompile(`"10\.0\.0\.300" is neither a valid CIDR range nor an IP address`),
+			},
+			{
+				Config:      testAccUserConfigWithAllowedIPRanges(username, "10.0.0.1/8"),
+				ExpectError: regexp.MustCompile(`"10\.0\.0\.1/8" has host bits set, did you mean "10\.0\.0\.0/8"\?`),
+			},
+			{
+				Config: testAccUserConfigWithAllowedIPRanges(username, "10.0.0.1", "2001:DB8::/32"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("warpgate_user.test", "allowed_ip_ranges.#", "2"),
+					resource.TestCheckResourceAttr("warpgate_user.test", "allowed_ip_ranges.0", "10.0.0.1/32"),
+					resource.TestCheckResourceAttr("warpgate_user.test", "allowed_ip_ranges.1", "2001:db8::/32"),
+				),
+			},
+			{
+				// Reordered and equivalent notations don't cause a diff
+				Config:   testAccUserConfigWithAllowedIPRanges(username, "2001:db8::/32", "10.0.0.1/32", "10.0.0.1"),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccUserConfigWithAllowedIPRanges(username, "10.0.0.1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("warpgate_user.test", "allowed_ip_ranges.#", "1"),
+					resource.TestCheckResourceAttr("warpgate_user.test", "allowed_ip_ranges.0", "10.0.0.1/32"),
+				),
+			},
+			{
+				ResourceName:      "warpgate_user.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccUserConfigWithAllowedIPRanges(username string, ranges ...string) string {
+	quoted := make([]string, len(ranges))
+	for i, r := range ranges {
+		quoted[i] = strconv.Quote(r)
+	}
+
+	return fmt.Sprintf(`
+resource "warpgate_user" "test" {
+  username = %q
+
+  allowed_ip_ranges = [%s]
+}
+`, username, strings.Join(quoted, ", "))
+}
+
+func TestUnsatisfiedCredentialPolicyWarnings(t *testing.T) {
+	diags := unsatisfiedCredentialPolicyWarnings("alice", map[string][]client.CredentialKind{
+		"ssh":  {client.CredentialKindPublicKey, client.CredentialKindTotp},
+		"http": {client.CredentialKindSso},
+	})
+
+	if len(diags) != 2 {
+		t.Fatalf("expected 2 warnings, got %d", len(diags))
+	}
+
+	for _, d := range diags {
+		if d.Severity != diag.Warning {
+			t.Errorf("expected a warning, got severity %v", d.Severity)
+		}
+	}
+
+	if diags[0].Summary != "User alice can't log in over HTTP" {
+		t.Errorf("unexpected summary %q", diags[0].Summary)
+	}
+	if !strings.Contains(diags[1].Detail, "requires PublicKey, Totp for ssh") {
+		t.Errorf("unexpected detail %q", diags[1].Detail)
+	}
+
+	if diags := unsatisfiedCredentialPolicyWarnings("alice", nil); len(diags) != 0 {
+		t.Errorf("expected no warnings, got %d", len(diags))
+	}
+}
+
+func TestAccUser_credentialPolicyWarnings(t *testing.T) {
+	username := acctest.RandomWithPrefix("tf-acc")
+
+	testAccTest(t, resource.TestCase{
+		CheckDestroy: testAccCheckDestroy("warpgate_user", testAccUserExists),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfigWithSSHPolicy(username, "PublicKey"),
+				Check:  testAccCheckUserReadWarnings("warpgate_user.test", "User "+username+" can't log in over SSH"),
+			},
+			{
+				// Adding a public key satisfies the policy
+				Config: testAccUserConfigWithSSHPolicy(username, "PublicKey") + fmt.Sprintf(`
+resource "warpgate_public_key_credential" "test" {
+  user_id    = warpgate_user.test.id
+  label      = "laptop"
+  public_key = %q
+}
+`, testAccPublicKey),
+				Check: testAccCheckUserReadWarnings("warpgate_user.test"),
+			},
+		},
+	})
+}
+
+// testAccCheckUserReadWarnings returns a check verifying the summaries of the
+// warnings refreshing the named user returns. The testing framework doesn't
+// expose warnings, so the check calls the read function itself.
+func testAccCheckUserReadWarnings(name string, summaries ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", name)
+		}
+
+		c, err := testAccClient()
+		if err != nil {
+			return err
+		}
+
+		d := resourceUser().Data(rs.Primary)
+		diags := resourceUserRead(context.Background(), d, &providerMeta{client: c})
+
+		var got []string
+		for _, d := range diags {
+			if d.Severity != diag.Warning {
+				return fmt.Errorf("unexpected error refreshing %s: %s: %s", name, d.Summary, d.Detail)
+			}
+			got = append(got, d.Summary)
+		}
+
+		if strings.Join(got, "\n") != strings.Join(summaries, "\n") {
+			return fmt.Errorf("expected warnings %q, got %q", summaries, got)
+		}
+
+		return nil
+	}
+}
+
+func testAccUserConfigWithSSHPolicy(username string, kinds ...string) string {
+	quoted := make([]string, len(kinds))
+	for i, kind := range kinds {
+		quoted[i] = strconv.Quote(kind)
+	}
+
+	return fmt.Sprintf(`
+resource "warpgate_user" "test" {
+  username = %q
+
+  credential_policy {
+    ssh = [%s]
+  }
+}
+`, username, strings.Join(quoted, ", "))
+}
+
+func testAccUserConfigWithKubernetesPolicy(username, kind string) string {
+	return fmt.Sprintf(`
+resource "warpgate_user" "test" {
+  username = %q
+
+  credential_policy {
+    kubernetes = [%q]
+  }
+}
+`, username, kind)
+}
+
+func testAccUserConfigWithPolicy(username string) string {
+	return fmt.Sprintf(`
+resource "warpgate_user" "test" {
+  username    = %q
+  description = "Updated"
+
+  credential_policy {
+    ssh = ["PublicKey"]
+  }
+
+  allowed_ip_ranges = ["10.0.0.0/8"]
+}
+`, username)
+}
+
+func testAccUserExists(ctx context.Context, c *client.Client, rs *terraform.ResourceState) (bool, error) {
+	user, err := c.GetUser(ctx, rs.Primary.ID)
+	return user != nil, err
+}
+
+func testAccUserDelete(ctx context.Context, c *client.Client, rs *terraform.ResourceState) error {
+	return c.DeleteUser(ctx, rs.Primary.ID)
+}
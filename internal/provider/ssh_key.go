@@ -0,0 +1,343 @@
+// Package provider implements the Terraform provider for Warpgate
+package provider
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/crypto/ssh"
+)
+
+// minRSAKeyBits is the smallest RSA key size accepted for public key
+// credentials.
+const minRSAKeyBits = 2048
+
+// Formats public keys are accepted in.
+const (
+	publicKeyFormatOpenSSH = "openssh"
+	publicKeyFormatRFC4716 = "rfc4716"
+	publicKeyFormatPEM     = "pem"
+	publicKeyFormatJWK     = "jwk"
+)
+
+// Markers of the RFC 4716 SSH public key file format.
+const (
+	rfc4716Begin = "---- BEGIN SSH2 PUBLIC KEY ----"
+	rfc4716End   = "---- END SSH2 PUBLIC KEY ----"
+)
+
+// parsePublicKey parses a public key in any of the accepted formats: OpenSSH
+// authorized_keys, RFC 4716, PEM SubjectPublicKeyInfo or PKCS #1, or JWK.
+func parsePublicKey(s string) (ssh.PublicKey, error) {
+	key, _, err := parsePublicKeyFormat(s)
+	return key, err
+}
+
+// parsePublicKeyFormat parses a public key like parsePublicKey and also returns
+// the format it was in.
+func parsePublicKeyFormat(s string) (ssh.PublicKey, string, error) {
+	trimmed := strings.TrimSpace(s)
+
+	var key ssh.PublicKey
+	var format string
+	var err error
+	switch {
+	case strings.HasPrefix(trimmed, rfc4716Begin):
+		format = publicKeyFormatRFC4716
+		key, err = parseRFC4716PublicKey(trimmed)
+	case strings.HasPrefix(trimmed, "-----BEGIN "):
+		format = publicKeyFormatPEM
+		key, err = parsePEMPublicKey(trimmed)
+	case strings.HasPrefix(trimmed, "{"):
+		format = publicKeyFormatJWK
+		key, err = parseJWKPublicKey(trimmed)
+	default:
+		format = publicKeyFormatOpenSSH
+		key, err = parseAuthorizedKey(s)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return key, format, nil
+}
+
+// parseRFC4716PublicKey parses a public key in the SSH public key file format
+// of RFC 4716. Headers such as the comment are ignored.
+func parseRFC4716PublicKey(s string) (ssh.PublicKey, error) {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	if strings.TrimSpace(lines[len(lines)-1]) != rfc4716End {
+		return nil, fmt.Errorf("is not a valid RFC 4716 public key: missing %q line", rfc4716End)
+	}
+
+	var body strings.Builder
+	continued := false
+	for _, line := range lines[1 : len(lines)-1] {
+		line = strings.TrimSpace(line)
+
+		// Header lines contain a colon and may be continued with a backslash
+		if continued || strings.Contains(line, ":") {
+			continued = strings.HasSuffix(line, "\\")
+			continue
+		}
+		body.WriteString(line)
+	}
+
+	wire, err := base64.StdEncoding.DecodeString(body.String())
+	if err != nil {
+		return nil, fmt.Errorf("is not a valid RFC 4716 public key: %w", err)
+	}
+
+	key, err := ssh.ParsePublicKey(wire)
+	if err != nil {
+		return nil, fmt.Errorf("is not a valid RFC 4716 public key: %w", err)
+	}
+
+	return key, nil
+}
+
+// parsePEMPublicKey parses a single PEM public key, either a SubjectPublicKeyInfo
+// ("PUBLIC KEY") as exported by most tools and key management services, or a
+// PKCS #1 RSA public key ("RSA PUBLIC KEY").
+func parsePEMPublicKey(s string) (ssh.PublicKey, error) {
+	block, rest := pem.Decode([]byte(s))
+	if block == nil {
+		return nil, fmt.Errorf("is not a valid PEM public key")
+	}
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return nil, fmt.Errorf("must contain a single public key")
+	}
+
+	var cryptoKey any
+	var err error
+	switch block.Type {
+	case "PUBLIC KEY":
+		cryptoKey, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		cryptoKey, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("must be a PEM block of type \"PUBLIC KEY\" or \"RSA PUBLIC KEY\", not %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("is not a valid PEM public key: %w", err)
+	}
+
+	key, err := ssh.NewPublicKey(cryptoKey)
+	if err != nil {
+		return nil, fmt.Errorf("is an unsupported PEM public key: %w", err)
+	}
+
+	return key, nil
+}
+
+// jwk holds the members of a JSON Web Key (RFC 7517) needed for public keys.
+type jwk struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	D   string `json:"d"`
+}
+
+// jwkCurves maps the JWK names of elliptic curves to the curves.
+var jwkCurves = map[string]struct {
+	ecdsa elliptic.Curve
+	ecdh  ecdh.Curve
+}{
+	"P-256": {elliptic.P256(), ecdh.P256()},
+	"P-384": {elliptic.P384(), ecdh.P384()},
+	"P-521": {elliptic.P521(), ecdh.P521()},
+}
+
+// parseJWKPublicKey parses a public JSON Web Key of type RSA, EC or OKP
+// (Ed25519).
+func parseJWKPublicKey(s string) (ssh.PublicKey, error) {
+	var k jwk
+	if err := json.Unmarshal([]byte(s), &k); err != nil {
+		return nil, fmt.Errorf("is not a valid JWK: %w", err)
+	}
+	if k.D != "" {
+		return nil, fmt.Errorf("must be a public JWK, this one contains a private key")
+	}
+
+	var cryptoKey any
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeJWKInt(k.N, "n")
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeJWKInt(k.E, "e")
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("is not a valid JWK: the RSA exponent is too large")
+		}
+		cryptoKey = &rsa.PublicKey{N: n, E: int(e.Int64())}
+	case "EC":
+		curve, ok := jwkCurves[k.Crv]
+		if !ok {
+			return nil, fmt.Errorf("uses the unsupported JWK curve %q", k.Crv)
+		}
+		x, err := decodeJWKBytes(k.X, "x")
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeJWKBytes(k.Y, "y")
+		if err != nil {
+			return nil, err
+		}
+
+		// The uncompressed point encoding is checked to lie on the curve
+		point := append(append([]byte{4}, x...), y...)
+		if _, err := curve.ecdh.NewPublicKey(point); err != nil {
+			return nil, fmt.Errorf("is not a valid JWK: %w", err)
+		}
+		cryptoKey = &ecdsa.PublicKey{Curve: curve.ecdsa, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("uses the unsupported JWK curve %q", k.Crv)
+		}
+		x, err := decodeJWKBytes(k.X, "x")
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("is not a valid JWK: an Ed25519 key must have %d bytes", ed25519.PublicKeySize)
+		}
+		cryptoKey = ed25519.PublicKey(x)
+	default:
+		return nil, fmt.Errorf("has the unsupported JWK key type %q", k.Kty)
+	}
+
+	key, err := ssh.NewPublicKey(cryptoKey)
+	if err != nil {
+		return nil, fmt.Errorf("is an unsupported JWK: %w", err)
+	}
+
+	return key, nil
+}
+
+// decodeJWKBytes decodes a base64url encoded JWK member.
+func decodeJWKBytes(value, member string) ([]byte, error) {
+	if value == "" {
+		return nil, fmt.Errorf("is not a valid JWK: missing %q", member)
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, fmt.Errorf("is not a valid JWK: %q is not base64url encoded", member)
+	}
+
+	return b, nil
+}
+
+// decodeJWKInt decodes a base64url encoded unsigned integer JWK member.
+func decodeJWKInt(value, member string) (*big.Int, error) {
+	b, err := decodeJWKBytes(value, member)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// parseAuthorizedKey parses an OpenSSH public key in authorized_keys format,
+// with an optional trailing comment.
+func parseAuthorizedKey(s string) (ssh.PublicKey, error) {
+	key, _, options, rest, err := ssh.ParseAuthorizedKey([]byte(s))
+	if err != nil {
+		return nil, fmt.Errorf("is not a valid OpenSSH public key: %w", err)
+	}
+	if len(options) > 0 {
+		return nil, fmt.Errorf("must not have authorized_keys options")
+	}
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return nil, fmt.Errorf("must contain a single public key")
+	}
+	if _, ok := key.(*ssh.Certificate); ok {
+		return nil, fmt.Errorf("must be a public key, not an SSH certificate")
+	}
+
+	return key, nil
+}
+
+// checkPublicKeyStrength rejects key types and sizes that are considered weak.
+func checkPublicKeyStrength(key ssh.PublicKey) error {
+	switch key.Type() {
+	case ssh.KeyAlgoDSA:
+		return fmt.Errorf("DSA keys are insecure and not accepted, use an Ed25519 or ECDSA key instead")
+	case ssh.KeyAlgoRSA:
+		if bits := publicKeyBits(key); bits < minRSAKeyBits {
+			return fmt.Errorf("RSA keys must have at least %d bits, this key has %d", minRSAKeyBits, bits)
+		}
+	}
+
+	return nil
+}
+
+// publicKeyBits returns the size of a public key in bits, or 0 for key types
+// that are not supported, such as DSA.
+func publicKeyBits(key ssh.PublicKey) int {
+	cryptoKey, ok := key.(ssh.CryptoPublicKey)
+	if !ok {
+		return 0
+	}
+
+	switch k := cryptoKey.CryptoPublicKey().(type) {
+	case *rsa.PublicKey:
+		return k.N.BitLen()
+	case *ecdsa.PublicKey:
+		return k.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return 256
+	}
+
+	return 0
+}
+
+// normalizePublicKey returns a public key in authorized_keys format without
+// its comment and extra whitespace, converting it from other formats. Values
+// that don't parse are returned as-is.
+func normalizePublicKey(s string) string {
+	key, err := parsePublicKey(s)
+	if err != nil {
+		return s
+	}
+	return string(bytes.TrimSpace(ssh.MarshalAuthorizedKey(key)))
+}
+
+// validatePublicKey checks that a value is a single public key in an accepted
+// format, of a type and size that is not considered weak.
+func validatePublicKey(v any, k string) ([]string, []error) {
+	key, err := parsePublicKey(v.(string))
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s %w", k, err)}
+	}
+
+	if err := checkPublicKeyStrength(key); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+
+	return nil, nil
+}
+
+// suppressEquivalentPublicKey suppresses diffs between notations of the same
+// public key, such as ones differing in their format, comment or whitespace.
+func suppressEquivalentPublicKey(k, oldValue, newValue string, d *schema.ResourceData) bool {
+	return normalizePublicKey(oldValue) == normalizePublicKey(newValue)
+}
@@ -0,0 +1,87 @@
+// Package provider implements the Terraform provider for Warpgate
+package provider
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"slices"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Algorithms key pairs can be generated with.
+const (
+	keyPairAlgorithmEd25519 = "ed25519"
+	keyPairAlgorithmRSA     = "rsa"
+)
+
+// rsaKeyPairBits are the sizes RSA key pairs can be generated with. Larger
+// keys take long to generate for little benefit, so they are not offered.
+var rsaKeyPairBits = []int{2048, 3072, 4096}
+
+// keyPairPolicy describes how a key pair is generated. RSABits is only used
+// for RSA keys.
+type keyPairPolicy struct {
+	Algorithm string
+	RSABits   int
+	Comment   string
+}
+
+// keyPair is a generated SSH key pair. The private key is encoded both in the
+// OpenSSH format and as unencrypted PKCS #8 PEM.
+type keyPair struct {
+	PublicKey         string
+	PrivateKeyOpenSSH string
+	PrivateKeyPEM     string
+}
+
+// generateKeyPair returns a new key pair following the policy, using a
+// cryptographically secure random source.
+func generateKeyPair(policy keyPairPolicy) (*keyPair, error) {
+	var privateKey crypto.Signer
+	switch policy.Algorithm {
+	case keyPairAlgorithmEd25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		privateKey = key
+	case keyPairAlgorithmRSA:
+		if !slices.Contains(rsaKeyPairBits, policy.RSABits) {
+			return nil, fmt.Errorf("RSA keys must have one of %v bits, got %d", rsaKeyPairBits, policy.RSABits)
+		}
+		key, err := rsa.GenerateKey(rand.Reader, policy.RSABits)
+		if err != nil {
+			return nil, err
+		}
+		privateKey = key
+	default:
+		return nil, fmt.Errorf("unsupported key algorithm %q", policy.Algorithm)
+	}
+
+	publicKey, err := ssh.NewPublicKey(privateKey.Public())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the public key: %w", err)
+	}
+
+	openSSHBlock, err := ssh.MarshalPrivateKey(privateKey, policy.Comment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the private key: %w", err)
+	}
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the private key: %w", err)
+	}
+
+	return &keyPair{
+		PublicKey:         normalizePublicKey(string(ssh.MarshalAuthorizedKey(publicKey))),
+		PrivateKeyOpenSSH: string(pem.EncodeToMemory(openSSHBlock)),
+		PrivateKeyPEM:     string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})),
+	}, nil
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestGenerateKeyPair(t *testing.T) {
+	for _, tc := range []struct {
+		policy keyPairPolicy
+		typ    string
+		bits   int
+	}{
+		{keyPairPolicy{Algorithm: keyPairAlgorithmEd25519, Comment: "ci"}, ssh.KeyAlgoED25519, 256},
+		{keyPairPolicy{Algorithm: keyPairAlgorithmRSA, RSABits: 2048}, ssh.KeyAlgoRSA, 2048},
+	} {
+		pair, err := generateKeyPair(tc.policy)
+		if err != nil {
+			t.Fatalf("generateKeyPair(%+v) returned error: %v", tc.policy, err)
+		}
+
+		publicKey, err := parsePublicKey(pair.PublicKey)
+		if err != nil {
+			t.Fatalf("generated public key %q doesn't parse: %v", pair.PublicKey, err)
+		}
+		if publicKey.Type() != tc.typ || publicKeyBits(publicKey) != tc.bits {
+			t.Errorf("expected a %d bit %s key, got a %d bit %s key", tc.bits, tc.typ, publicKeyBits(publicKey), publicKey.Type())
+		}
+
+		// Both encodings of the private key belong to the public key
+		for name, encoded := range map[string]string{"OpenSSH": pair.PrivateKeyOpenSSH, "PEM": pair.PrivateKeyPEM} {
+			signer, err := ssh.ParsePrivateKey([]byte(encoded))
+			if err != nil {
+				t.Fatalf("%s private key doesn't parse: %v", name, err)
+			}
+			if ssh.FingerprintSHA256(signer.PublicKey()) != ssh.FingerprintSHA256(publicKey) {
+				t.Errorf("%s private key doesn't match the public key", name)
+			}
+		}
+	}
+
+	for _, bits := range []int{1024, 2047, 8192} {
+		if _, err := generateKeyPair(keyPairPolicy{Algorithm: keyPairAlgorithmRSA, RSABits: bits}); err == nil {
+			t.Errorf("expected an error for a %d bit RSA key", bits)
+		}
+	}
+	if _, err := generateKeyPair(keyPairPolicy{Algorithm: "dsa"}); err == nil {
+		t.Error("expected an error for an unsupported algorithm")
+	}
+}
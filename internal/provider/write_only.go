@@ -0,0 +1,84 @@
+// Package provider implements the Terraform provider for Warpgate
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// writeOnlySuffix marks write-only variants of secret attributes. Each
+// "<name>_wo" attribute comes with a "<name>_wo_version" attribute that is
+// stored in the state and triggers sending the secret again when it changes.
+const writeOnlySuffix = "_wo"
+
+// writeOnlyString returns the configured value of a write-only string
+// attribute, which d.Get never returns. It returns "" if the attribute is not
+// set.
+func writeOnlyString(d *schema.ResourceData, path cty.Path) (string, error) {
+	value, diags := d.GetRawConfigAt(path)
+	if diags.HasError() {
+		return "", fmt.Errorf("failed to read write-only attribute: %s", diags[0].Summary)
+	}
+
+	if value.IsNull() || !value.IsKnown() || !value.Type().Equals(cty.String) {
+		return "", nil
+	}
+
+	return value.AsString(), nil
+}
+
+// readWriteOnlyAttributes copies the configured values of the write-only
+// attributes in a block, including nested blocks, into the map returned by
+// d.Get for that block.
+func readWriteOnlyAttributes(d *schema.ResourceData, path cty.Path, block map[string]any) error {
+	for key, value := range block {
+		if items, ok := value.([]any); ok {
+			for i, item := range items {
+				if nested, ok := item.(map[string]any); ok {
+					if err := readWriteOnlyAttributes(d, path.GetAttr(key).IndexInt(i), nested); err != nil {
+						return err
+					}
+				}
+			}
+			continue
+		}
+
+		if !strings.HasSuffix(key, writeOnlySuffix) {
+			continue
+		}
+
+		secret, err := writeOnlyString(d, path.GetAttr(key))
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		block[key] = secret
+	}
+
+	return nil
+}
+
+// secretOrWriteOnly returns the value of a secret attribute in a block, or of
+// its write-only variant if that is set instead.
+func secretOrWriteOnly(block map[string]any, key string) string {
+	if secret, ok := block[key+writeOnlySuffix].(string); ok && secret != "" {
+		return secret
+	}
+
+	secret, _ := block[key].(string)
+	return secret
+}
+
+// setSecretAttribute sets a secret read back from the API in a block, unless
+// it is managed through its write-only variant, in which case only the version
+// of the write-only attribute is kept.
+func setSecretAttribute(block map[string]any, key string, value any, writeOnlyVersion int) {
+	if writeOnlyVersion != 0 {
+		block[key+writeOnlySuffix+"_version"] = writeOnlyVersion
+		return
+	}
+
+	block[key] = value
+}
@@ -0,0 +1,1366 @@
+// Package warpgatetest provides an in-process fake of the Warpgate admin API for
+// tests. The fake keeps users, roles, targets, target groups, credentials, tickets
+// and parameters in memory and mimics the endpoints and status codes of the real
+// server closely enough to exercise the provider without a running Warpgate.
+package warpgatetest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+)
+
+// DefaultVersion is the Warpgate version the fake server reports by default. It
+// is recent enough for every feature the provider supports.
+const DefaultVersion = "v0.17.0"
+
+// Server is an in-memory fake of the Warpgate admin API served over HTTP.
+type Server struct {
+	*httptest.Server
+
+	// Token is the API token required in the X-Warpgate-Token header. If empty,
+	// requests are not authenticated.
+	Token string
+
+	// Version is the Warpgate version reported by the info endpoint. If empty,
+	// no version is reported.
+	Version string
+
+	mu sync.Mutex
+
+	users          map[string]*client.User
+	roles          map[string]*client.Role
+	targets        map[string]*client.Target
+	targetGroups   map[string]*client.TargetGroup
+	userRoles      map[string]map[string]bool
+	targetRoles    map[string]map[string]bool
+	passwords      map[string]map[string]string
+	publicKeys     map[string]map[string]*client.PublicKeyCredential
+	certificates   map[string]map[string]*client.CertificateCredential
+	ssoCredentials map[string]map[string]*client.SsoCredential
+	tickets        map[string]*client.Ticket
+	parameters     client.ParameterValues
+	ownKeys        []client.SSHOwnKey
+
+	// caCert and caKey sign issued certificate credentials. They are created
+	// when the first certificate is issued.
+	caCert *x509.Certificate
+	caKey  *ecdsa.PrivateKey
+}
+
+// NewServer starts a fake Warpgate server requiring the given API token. The admin
+// API is served below client.AdminAPIPath, so the server URL can be used as the
+// provider host as-is. Callers must call Close when done.
+func NewServer(token string) *Server {
+	s := &Server{
+		Token:          token,
+		Version:        DefaultVersion,
+		users:          make(map[string]*client.User),
+		roles:          make(map[string]*client.Role),
+		targets:        make(map[string]*client.Target),
+		targetGroups:   make(map[string]*client.TargetGroup),
+		userRoles:      make(map[string]map[string]bool),
+		targetRoles:    make(map[string]map[string]bool),
+		passwords:      make(map[string]map[string]string),
+		publicKeys:     make(map[string]map[string]*client.PublicKeyCredential),
+		certificates:   make(map[string]map[string]*client.CertificateCredential),
+		ssoCredentials: make(map[string]map[string]*client.SsoCredential),
+		tickets:        make(map[string]*client.Ticket),
+		parameters: client.ParameterValues{
+			AllowOwnCredentialManagement: true,
+			SSHClientAuthPublickey:       true,
+			SSHClientAuthPassword:        true,
+			ShowSessionMenu:              true,
+		},
+		ownKeys: []client.SSHOwnKey{
+			{Kind: "ssh-ed25519", PublicKeyBase64: "AAAAC3NzaC1lZDI1NTE5AAAAIFakeWarpgateHostKeyForTestsOnly0000000000"},
+		},
+	}
+
+	api := http.NewServeMux()
+	s.registerRoutes(api)
+
+	root := http.NewServeMux()
+	root.Handle(client.AdminAPIPath+"/", http.StripPrefix(client.AdminAPIPath, s.authenticate(api)))
+	root.HandleFunc("GET "+client.PublicAPIPath+"/info", s.getInfo)
+
+	s.Server = httptest.NewServer(root)
+
+	return s
+}
+
+// registerRoutes registers the handlers for all supported admin API endpoints.
+func (s *Server) registerRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /users", s.listUsers)
+	mux.HandleFunc("POST /users", s.createUser)
+	mux.HandleFunc("GET /users/{id}", s.getUser)
+	mux.HandleFunc("PUT /users/{id}", s.updateUser)
+	mux.HandleFunc("DELETE /users/{id}", s.deleteUser)
+
+	mux.HandleFunc("GET /users/{id}/roles", s.listUserRoles)
+	mux.HandleFunc("POST /users/{id}/roles/{role_id}", s.addUserRole)
+	mux.HandleFunc("DELETE /users/{id}/roles/{role_id}", s.deleteUserRole)
+
+	mux.HandleFunc("GET /users/{id}/credentials/passwords", s.listPasswordCredentials)
+	mux.HandleFunc("POST /users/{id}/credentials/passwords", s.createPasswordCredential)
+	mux.HandleFunc("DELETE /users/{id}/credentials/passwords/{credential_id}", s.deletePasswordCredential)
+
+	mux.HandleFunc("GET /users/{id}/credentials/public-keys", s.listPublicKeyCredentials)
+	mux.HandleFunc("POST /users/{id}/credentials/public-keys", s.createPublicKeyCredential)
+	mux.HandleFunc("PUT /users/{id}/credentials/public-keys/{credential_id}", s.updatePublicKeyCredential)
+	mux.HandleFunc("DELETE /users/{id}/credentials/public-keys/{credential_id}", s.deletePublicKeyCredential)
+
+	mux.HandleFunc("GET /users/{id}/credentials/certificates", s.listCertificateCredentials)
+	mux.HandleFunc("POST /users/{id}/credentials/certificates", s.createCertificateCredential)
+	mux.HandleFunc("POST /users/{id}/credentials/certificates/issue", s.issueCertificateCredential)
+	mux.HandleFunc("PUT /users/{id}/credentials/certificates/{credential_id}", s.updateCertificateCredential)
+	mux.HandleFunc("DELETE /users/{id}/credentials/certificates/{credential_id}", s.deleteCertificateCredential)
+
+	mux.HandleFunc("GET /users/{id}/credentials/sso", s.listSsoCredentials)
+	mux.HandleFunc("POST /users/{id}/credentials/sso", s.createSsoCredential)
+	mux.HandleFunc("PUT /users/{id}/credentials/sso/{credential_id}", s.updateSsoCredential)
+	mux.HandleFunc("DELETE /users/{id}/credentials/sso/{credential_id}", s.deleteSsoCredential)
+
+	mux.HandleFunc("GET /users/{id}/credentials/otp", s.listOtpCredentials)
+
+	mux.HandleFunc("GET /roles", s.listRoles)
+	mux.HandleFunc("POST /roles", s.createRole)
+	mux.HandleFunc("GET /role/{id}", s.getRole)
+	mux.HandleFunc("PUT /role/{id}", s.updateRole)
+	mux.HandleFunc("DELETE /role/{id}", s.deleteRole)
+
+	mux.HandleFunc("GET /targets", s.listTargets)
+	mux.HandleFunc("POST /targets", s.createTarget)
+	mux.HandleFunc("GET /targets/{id}", s.getTarget)
+	mux.HandleFunc("PUT /targets/{id}", s.updateTarget)
+	mux.HandleFunc("DELETE /targets/{id}", s.deleteTarget)
+
+	mux.HandleFunc("GET /targets/{id}/roles", s.listTargetRoles)
+	mux.HandleFunc("POST /targets/{id}/roles/{role_id}", s.addTargetRole)
+	mux.HandleFunc("DELETE /targets/{id}/roles/{role_id}", s.deleteTargetRole)
+
+	mux.HandleFunc("GET /target-groups", s.listTargetGroups)
+	mux.HandleFunc("POST /target-groups", s.createTargetGroup)
+	mux.HandleFunc("GET /target-groups/{id}", s.getTargetGroup)
+	mux.HandleFunc("PUT /target-groups/{id}", s.updateTargetGroup)
+	mux.HandleFunc("DELETE /target-groups/{id}", s.deleteTargetGroup)
+
+	mux.HandleFunc("GET /tickets", s.listTickets)
+	mux.HandleFunc("POST /tickets", s.createTicket)
+	mux.HandleFunc("DELETE /tickets/{id}", s.deleteTicket)
+
+	mux.HandleFunc("GET /parameters", s.getParameters)
+	mux.HandleFunc("PUT /parameters", s.updateParameters)
+
+	mux.HandleFunc("GET /ssh/own-keys", s.listSSHOwnKeys)
+}
+
+// authenticate rejects requests that don't carry the expected API token and
+// serializes access to the in-memory store.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.Token != "" && r.Header.Get("X-Warpgate-Token") != s.Token {
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Users
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request) {
+	search := r.URL.Query().Get("search")
+
+	users := []client.User{}
+	for _, user := range s.users {
+		if matchesSearch(user.Username, search) {
+			users = append(users, *user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+
+	writeJSON(w, http.StatusOK, users)
+}
+
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request) {
+	var req client.UserCreateRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if req.Username == "" {
+		writeError(w, http.StatusBadRequest, "username must not be empty")
+		return
+	}
+
+	for _, user := range s.users {
+		if user.Username == req.Username {
+			writeError(w, http.StatusConflict, "a user with this username already exists")
+			return
+		}
+	}
+
+	user := &client.User{
+		ID:          newID(),
+		Username:    req.Username,
+		Description: req.Description,
+	}
+	s.users[user.ID] = user
+
+	writeJSON(w, http.StatusCreated, user)
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) updateUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req client.UserUpdateRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if req.Username == "" {
+		writeError(w, http.StatusBadRequest, "username must not be empty")
+		return
+	}
+
+	user.Username = req.Username
+	user.Description = req.Description
+	user.CredentialPolicy = req.CredentialPolicy
+	user.AllowedIPRanges = req.AllowedIPRanges
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	delete(s.users, user.ID)
+	delete(s.userRoles, user.ID)
+	delete(s.passwords, user.ID)
+	delete(s.publicKeys, user.ID)
+	delete(s.certificates, user.ID)
+	delete(s.ssoCredentials, user.ID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// User roles
+
+func (s *Server) listUserRoles(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.rolesByID(s.userRoles[user.ID]))
+}
+
+func (s *Server) addUserRole(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	role, ok := s.lookupRoleParam(w, r, "role_id")
+	if !ok {
+		return
+	}
+
+	if s.userRoles[user.ID][role.ID] {
+		writeError(w, http.StatusConflict, "role is already assigned to the user")
+		return
+	}
+
+	if s.userRoles[user.ID] == nil {
+		s.userRoles[user.ID] = make(map[string]bool)
+	}
+	s.userRoles[user.ID][role.ID] = true
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) deleteUserRole(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	role, ok := s.lookupRoleParam(w, r, "role_id")
+	if !ok {
+		return
+	}
+
+	if !s.userRoles[user.ID][role.ID] {
+		writeError(w, http.StatusNotFound, "role is not assigned to the user")
+		return
+	}
+
+	delete(s.userRoles[user.ID], role.ID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Password credentials
+
+func (s *Server) listPasswordCredentials(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	creds := []client.PasswordCredential{}
+	for id := range s.passwords[user.ID] {
+		creds = append(creds, client.PasswordCredential{ID: id})
+	}
+	sort.Slice(creds, func(i, j int) bool { return creds[i].ID < creds[j].ID })
+
+	writeJSON(w, http.StatusOK, creds)
+}
+
+func (s *Server) createPasswordCredential(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req client.PasswordCredential
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if req.Password == "" {
+		writeError(w, http.StatusBadRequest, "password must not be empty")
+		return
+	}
+
+	id := newID()
+	if s.passwords[user.ID] == nil {
+		s.passwords[user.ID] = make(map[string]string)
+	}
+	s.passwords[user.ID][id] = req.Password
+
+	writeJSON(w, http.StatusCreated, client.PasswordCredential{ID: id})
+}
+
+func (s *Server) deletePasswordCredential(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	id := r.PathValue("credential_id")
+	if _, ok := s.passwords[user.ID][id]; !ok {
+		writeError(w, http.StatusNotFound, "credential not found")
+		return
+	}
+
+	delete(s.passwords[user.ID], id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Public key credentials
+
+func (s *Server) listPublicKeyCredentials(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	creds := []client.PublicKeyCredential{}
+	for _, cred := range s.publicKeys[user.ID] {
+		creds = append(creds, *cred)
+	}
+	sort.Slice(creds, func(i, j int) bool { return creds[i].DateAdded+creds[i].ID < creds[j].DateAdded+creds[j].ID })
+
+	writeJSON(w, http.StatusOK, creds)
+}
+
+func (s *Server) createPublicKeyCredential(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req client.PublicKeyCredential
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if req.OpensshPublicKey == "" {
+		writeError(w, http.StatusBadRequest, "openssh_public_key must not be empty")
+		return
+	}
+
+	cred := &client.PublicKeyCredential{
+		ID:               newID(),
+		Label:            req.Label,
+		OpensshPublicKey: stripKeyComment(req.OpensshPublicKey),
+		DateAdded:        time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	if s.publicKeys[user.ID] == nil {
+		s.publicKeys[user.ID] = make(map[string]*client.PublicKeyCredential)
+	}
+	s.publicKeys[user.ID][cred.ID] = cred
+
+	writeJSON(w, http.StatusCreated, cred)
+}
+
+func (s *Server) updatePublicKeyCredential(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	cred, ok := s.publicKeys[user.ID][r.PathValue("credential_id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "credential not found")
+		return
+	}
+
+	var req client.PublicKeyCredential
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	cred.Label = req.Label
+	cred.OpensshPublicKey = stripKeyComment(req.OpensshPublicKey)
+
+	writeJSON(w, http.StatusOK, cred)
+}
+
+// stripKeyComment drops the comment of an OpenSSH public key, as Warpgate
+// doesn't store it.
+func stripKeyComment(key string) string {
+	fields := strings.Fields(key)
+	if len(fields) < 2 {
+		return key
+	}
+	return fields[0] + " " + fields[1]
+}
+
+func (s *Server) deletePublicKeyCredential(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	id := r.PathValue("credential_id")
+	if _, ok := s.publicKeys[user.ID][id]; !ok {
+		writeError(w, http.StatusNotFound, "credential not found")
+		return
+	}
+
+	delete(s.publicKeys[user.ID], id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Certificate credentials
+
+func (s *Server) listCertificateCredentials(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	creds := []client.CertificateCredential{}
+	for _, cred := range s.certificates[user.ID] {
+		creds = append(creds, *cred)
+	}
+	sort.Slice(creds, func(i, j int) bool { return creds[i].DateAdded+creds[i].ID < creds[j].DateAdded+creds[j].ID })
+
+	writeJSON(w, http.StatusOK, creds)
+}
+
+func (s *Server) createCertificateCredential(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req client.CertificateCredential
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	block, _ := pem.Decode([]byte(req.CertificatePEM))
+	if block == nil || block.Type != "CERTIFICATE" {
+		writeError(w, http.StatusBadRequest, "certificate_pem must be a PEM certificate")
+		return
+	}
+	if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid certificate: %s", err))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, s.addCertificateCredential(user, req.Label, block.Bytes))
+}
+
+func (s *Server) issueCertificateCredential(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req client.CertificateCredentialIssueRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	block, _ := pem.Decode([]byte(req.CSRPEM))
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		writeError(w, http.StatusBadRequest, "csr_pem must be a PEM certificate request")
+		return
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err == nil {
+		err = csr.CheckSignature()
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid certificate request: %s", err))
+		return
+	}
+
+	der, err := s.signCertificate(user, csr)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, s.addCertificateCredential(user, req.Label, der))
+}
+
+func (s *Server) addCertificateCredential(user *client.User, label string, der []byte) *client.CertificateCredential {
+	cred := &client.CertificateCredential{
+		ID:             newID(),
+		Label:          label,
+		CertificatePEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		DateAdded:      time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	if s.certificates[user.ID] == nil {
+		s.certificates[user.ID] = make(map[string]*client.CertificateCredential)
+	}
+	s.certificates[user.ID][cred.ID] = cred
+
+	return cred
+}
+
+// signCertificate issues a client certificate for the user from a CSR, signed
+// by a CA that is created on first use.
+func (s *Server) signCertificate(user *client.User, csr *x509.CertificateRequest) ([]byte, error) {
+	now := time.Now()
+
+	if s.caCert == nil {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate CA key: %w", err)
+		}
+
+		template := &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: "Warpgate test CA"},
+			NotBefore:             now.Add(-time.Hour),
+			NotAfter:              now.AddDate(10, 0, 0),
+			KeyUsage:              x509.KeyUsageCertSign,
+			BasicConstraintsValid: true,
+			IsCA:                  true,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CA certificate: %w", err)
+		}
+		if s.caCert, err = x509.ParseCertificate(der); err != nil {
+			return nil, fmt.Errorf("failed to parse CA certificate: %w", err)
+		}
+		s.caKey = key
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: user.Username},
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     now.AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	return x509.CreateCertificate(rand.Reader, template, s.caCert, csr.PublicKey, s.caKey)
+}
+
+func (s *Server) updateCertificateCredential(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	cred, ok := s.certificates[user.ID][r.PathValue("credential_id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "credential not found")
+		return
+	}
+
+	var req client.CertificateCredential
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	cred.Label = req.Label
+
+	writeJSON(w, http.StatusOK, cred)
+}
+
+func (s *Server) deleteCertificateCredential(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	id := r.PathValue("credential_id")
+	if _, ok := s.certificates[user.ID][id]; !ok {
+		writeError(w, http.StatusNotFound, "credential not found")
+		return
+	}
+
+	delete(s.certificates[user.ID], id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SSO credentials
+
+func (s *Server) listSsoCredentials(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	creds := []client.SsoCredential{}
+	for _, cred := range s.ssoCredentials[user.ID] {
+		creds = append(creds, *cred)
+	}
+	sort.Slice(creds, func(i, j int) bool { return creds[i].ID < creds[j].ID })
+
+	writeJSON(w, http.StatusOK, creds)
+}
+
+func (s *Server) createSsoCredential(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req client.SsoCredential
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if req.Email == "" {
+		writeError(w, http.StatusBadRequest, "email must not be empty")
+		return
+	}
+
+	cred := &client.SsoCredential{
+		ID:       newID(),
+		Provider: req.Provider,
+		Email:    req.Email,
+	}
+	if s.ssoCredentials[user.ID] == nil {
+		s.ssoCredentials[user.ID] = make(map[string]*client.SsoCredential)
+	}
+	s.ssoCredentials[user.ID][cred.ID] = cred
+
+	writeJSON(w, http.StatusCreated, cred)
+}
+
+func (s *Server) updateSsoCredential(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	cred, ok := s.ssoCredentials[user.ID][r.PathValue("credential_id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "credential not found")
+		return
+	}
+
+	var req client.SsoCredential
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	cred.Provider = req.Provider
+	cred.Email = req.Email
+
+	writeJSON(w, http.StatusOK, cred)
+}
+
+func (s *Server) deleteSsoCredential(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.lookupUser(w, r)
+	if !ok {
+		return
+	}
+
+	id := r.PathValue("credential_id")
+	if _, ok := s.ssoCredentials[user.ID][id]; !ok {
+		writeError(w, http.StatusNotFound, "credential not found")
+		return
+	}
+
+	delete(s.ssoCredentials[user.ID], id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TOTP credentials
+
+// listOtpCredentials lists the TOTP credentials of a user. TOTP secrets are
+// enrolled by users themselves, which the fake server doesn't support, so users
+// never have any.
+func (s *Server) listOtpCredentials(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.lookupUser(w, r); !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, []client.OtpCredential{})
+}
+
+// Roles
+
+func (s *Server) listRoles(w http.ResponseWriter, r *http.Request) {
+	search := r.URL.Query().Get("search")
+
+	roles := []client.Role{}
+	for _, role := range s.roles {
+		if matchesSearch(role.Name, search) {
+			roles = append(roles, *role)
+		}
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+
+	writeJSON(w, http.StatusOK, roles)
+}
+
+func (s *Server) createRole(w http.ResponseWriter, r *http.Request) {
+	var req client.RoleCreateRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name must not be empty")
+		return
+	}
+
+	for _, role := range s.roles {
+		if role.Name == req.Name {
+			writeError(w, http.StatusConflict, "a role with this name already exists")
+			return
+		}
+	}
+
+	role := &client.Role{
+		ID:          newID(),
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	s.roles[role.ID] = role
+
+	writeJSON(w, http.StatusCreated, role)
+}
+
+func (s *Server) getRole(w http.ResponseWriter, r *http.Request) {
+	role, ok := s.lookupRoleParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, role)
+}
+
+func (s *Server) updateRole(w http.ResponseWriter, r *http.Request) {
+	role, ok := s.lookupRoleParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req client.RoleCreateRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name must not be empty")
+		return
+	}
+
+	role.Name = req.Name
+	role.Description = req.Description
+
+	writeJSON(w, http.StatusOK, role)
+}
+
+func (s *Server) deleteRole(w http.ResponseWriter, r *http.Request) {
+	role, ok := s.lookupRoleParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	delete(s.roles, role.ID)
+	for _, roles := range s.userRoles {
+		delete(roles, role.ID)
+	}
+	for _, roles := range s.targetRoles {
+		delete(roles, role.ID)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Targets
+
+func (s *Server) listTargets(w http.ResponseWriter, r *http.Request) {
+	search := r.URL.Query().Get("search")
+
+	targets := []client.Target{}
+	for _, target := range s.targets {
+		if matchesSearch(target.Name, search) {
+			targets = append(targets, s.targetWithRoles(target))
+		}
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Name < targets[j].Name })
+
+	writeJSON(w, http.StatusOK, targets)
+}
+
+func (s *Server) createTarget(w http.ResponseWriter, r *http.Request) {
+	var req client.TargetDataRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if !s.validateTarget(w, "", &req) {
+		return
+	}
+
+	target := &client.Target{
+		ID:          newID(),
+		Name:        req.Name,
+		Description: req.Description,
+		GroupId:     req.GroupId,
+		Options:     req.Options,
+	}
+	s.targets[target.ID] = target
+
+	writeJSON(w, http.StatusCreated, s.targetWithRoles(target))
+}
+
+func (s *Server) getTarget(w http.ResponseWriter, r *http.Request) {
+	target, ok := s.lookupTarget(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.targetWithRoles(target))
+}
+
+func (s *Server) updateTarget(w http.ResponseWriter, r *http.Request) {
+	target, ok := s.lookupTarget(w, r)
+	if !ok {
+		return
+	}
+
+	var req client.TargetDataRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if !s.validateTarget(w, target.ID, &req) {
+		return
+	}
+
+	target.Name = req.Name
+	target.Description = req.Description
+	target.GroupId = req.GroupId
+	target.Options = req.Options
+
+	writeJSON(w, http.StatusOK, s.targetWithRoles(target))
+}
+
+func (s *Server) deleteTarget(w http.ResponseWriter, r *http.Request) {
+	target, ok := s.lookupTarget(w, r)
+	if !ok {
+		return
+	}
+
+	delete(s.targets, target.ID)
+	delete(s.targetRoles, target.ID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateTarget checks a target create or update request, writing an error
+// response and returning false if it is invalid.
+func (s *Server) validateTarget(w http.ResponseWriter, id string, req *client.TargetDataRequest) bool {
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name must not be empty")
+		return false
+	}
+
+	for _, target := range s.targets {
+		if target.ID != id && target.Name == req.Name {
+			writeError(w, http.StatusConflict, "a target with this name already exists")
+			return false
+		}
+	}
+
+	if req.GroupId != "" {
+		if _, ok := s.targetGroups[req.GroupId]; !ok {
+			writeError(w, http.StatusBadRequest, "target group not found")
+			return false
+		}
+	}
+
+	options, ok := req.Options.(map[string]any)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "options must be an object")
+		return false
+	}
+
+	switch options["kind"] {
+	case "Ssh", "Http", "MySql", "Postgres", "Kubernetes":
+		return true
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported target kind: %v", options["kind"]))
+		return false
+	}
+}
+
+// targetWithRoles returns a copy of the target with allow_roles populated from
+// the current role assignments.
+func (s *Server) targetWithRoles(target *client.Target) client.Target {
+	result := *target
+	result.AllowRoles = []string{}
+	for _, role := range s.rolesByID(s.targetRoles[target.ID]) {
+		result.AllowRoles = append(result.AllowRoles, role.Name)
+	}
+	return result
+}
+
+// Target roles
+
+func (s *Server) listTargetRoles(w http.ResponseWriter, r *http.Request) {
+	target, ok := s.lookupTarget(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.rolesByID(s.targetRoles[target.ID]))
+}
+
+func (s *Server) addTargetRole(w http.ResponseWriter, r *http.Request) {
+	target, ok := s.lookupTarget(w, r)
+	if !ok {
+		return
+	}
+
+	role, ok := s.lookupRoleParam(w, r, "role_id")
+	if !ok {
+		return
+	}
+
+	if s.targetRoles[target.ID][role.ID] {
+		writeError(w, http.StatusConflict, "role is already assigned to the target")
+		return
+	}
+
+	if s.targetRoles[target.ID] == nil {
+		s.targetRoles[target.ID] = make(map[string]bool)
+	}
+	s.targetRoles[target.ID][role.ID] = true
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) deleteTargetRole(w http.ResponseWriter, r *http.Request) {
+	target, ok := s.lookupTarget(w, r)
+	if !ok {
+		return
+	}
+
+	role, ok := s.lookupRoleParam(w, r, "role_id")
+	if !ok {
+		return
+	}
+
+	if !s.targetRoles[target.ID][role.ID] {
+		writeError(w, http.StatusNotFound, "role is not assigned to the target")
+		return
+	}
+
+	delete(s.targetRoles[target.ID], role.ID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Target groups
+
+func (s *Server) listTargetGroups(w http.ResponseWriter, r *http.Request) {
+	targetGroups := []client.TargetGroup{}
+	for _, targetGroup := range s.targetGroups {
+		targetGroups = append(targetGroups, *targetGroup)
+	}
+	sort.Slice(targetGroups, func(i, j int) bool { return targetGroups[i].Name < targetGroups[j].Name })
+
+	writeJSON(w, http.StatusOK, targetGroups)
+}
+
+func (s *Server) createTargetGroup(w http.ResponseWriter, r *http.Request) {
+	var req client.TargetGroupCreateRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name must not be empty")
+		return
+	}
+
+	targetGroup := &client.TargetGroup{
+		ID:          newID(),
+		Name:        req.Name,
+		Description: req.Description,
+		Color:       req.Color,
+	}
+	s.targetGroups[targetGroup.ID] = targetGroup
+
+	writeJSON(w, http.StatusCreated, targetGroup)
+}
+
+func (s *Server) getTargetGroup(w http.ResponseWriter, r *http.Request) {
+	targetGroup, ok := s.lookupTargetGroup(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, targetGroup)
+}
+
+func (s *Server) updateTargetGroup(w http.ResponseWriter, r *http.Request) {
+	targetGroup, ok := s.lookupTargetGroup(w, r)
+	if !ok {
+		return
+	}
+
+	var req client.TargetGroupCreateRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	targetGroup.Name = req.Name
+	targetGroup.Description = req.Description
+	targetGroup.Color = req.Color
+
+	writeJSON(w, http.StatusOK, targetGroup)
+}
+
+func (s *Server) deleteTargetGroup(w http.ResponseWriter, r *http.Request) {
+	targetGroup, ok := s.lookupTargetGroup(w, r)
+	if !ok {
+		return
+	}
+
+	delete(s.targetGroups, targetGroup.ID)
+	for _, target := range s.targets {
+		if target.GroupId == targetGroup.ID {
+			target.GroupId = ""
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Tickets
+
+func (s *Server) listTickets(w http.ResponseWriter, r *http.Request) {
+	tickets := []client.Ticket{}
+	for _, ticket := range s.tickets {
+		tickets = append(tickets, *ticket)
+	}
+	sort.Slice(tickets, func(i, j int) bool { return tickets[i].Created+tickets[i].ID < tickets[j].Created+tickets[j].ID })
+
+	writeJSON(w, http.StatusOK, tickets)
+}
+
+func (s *Server) createTicket(w http.ResponseWriter, r *http.Request) {
+	var req client.TicketCreateRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if req.Username == "" || req.TargetName == "" {
+		writeError(w, http.StatusBadRequest, "username and target_name must not be empty")
+		return
+	}
+
+	ticket := &client.Ticket{
+		ID:          newID(),
+		Username:    req.Username,
+		Description: req.Description,
+		Target:      req.TargetName,
+		Expiry:      req.Expiry,
+		Created:     time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	if req.NumberOfUses > 0 {
+		usesLeft := req.NumberOfUses
+		ticket.UsesLeft = &usesLeft
+	}
+	s.tickets[ticket.ID] = ticket
+
+	writeJSON(w, http.StatusCreated, client.TicketAndSecret{
+		Ticket: *ticket,
+		Secret: newID(),
+	})
+}
+
+func (s *Server) deleteTicket(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !isValidID(id) {
+		writeError(w, http.StatusBadRequest, "invalid ticket ID")
+		return
+	}
+
+	if _, ok := s.tickets[id]; !ok {
+		writeError(w, http.StatusNotFound, "ticket not found")
+		return
+	}
+
+	delete(s.tickets, id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Parameters
+
+func (s *Server) getParameters(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.parameters)
+}
+
+func (s *Server) updateParameters(w http.ResponseWriter, r *http.Request) {
+	var req client.ParametersUpdateRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.parameters = client.ParameterValues(req)
+
+	// The real server answers with 201 and no body
+	w.WriteHeader(http.StatusCreated)
+}
+
+// Info
+
+func (s *Server) getInfo(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sshPort, httpPort, mysqlPort, postgresPort, kubernetesPort := 2222, 8888, 33306, 55432, 8443
+	info := client.ServerInfo{
+		Ports: client.ServerPorts{
+			SSH:        &sshPort,
+			HTTP:       &httpPort,
+			MySQL:      &mysqlPort,
+			Postgres:   &postgresPort,
+			Kubernetes: &kubernetesPort,
+		},
+	}
+	if s.Version != "" {
+		version := s.Version
+		info.Version = &version
+	}
+
+	writeJSON(w, http.StatusOK, info)
+}
+
+// SSH
+
+func (s *Server) listSSHOwnKeys(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.ownKeys)
+}
+
+// Lookup helpers. Like the real server, malformed IDs are rejected with 400 and
+// unknown IDs with 404.
+
+func (s *Server) lookupUser(w http.ResponseWriter, r *http.Request) (*client.User, bool) {
+	id := r.PathValue("id")
+	if !isValidID(id) {
+		writeError(w, http.StatusBadRequest, "invalid user ID")
+		return nil, false
+	}
+
+	user, ok := s.users[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "user not found")
+		return nil, false
+	}
+
+	return user, true
+}
+
+func (s *Server) lookupRoleParam(w http.ResponseWriter, r *http.Request, param string) (*client.Role, bool) {
+	id := r.PathValue(param)
+	if !isValidID(id) {
+		writeError(w, http.StatusBadRequest, "invalid role ID")
+		return nil, false
+	}
+
+	role, ok := s.roles[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "role not found")
+		return nil, false
+	}
+
+	return role, true
+}
+
+func (s *Server) lookupTarget(w http.ResponseWriter, r *http.Request) (*client.Target, bool) {
+	id := r.PathValue("id")
+	if !isValidID(id) {
+		writeError(w, http.StatusBadRequest, "invalid target ID")
+		return nil, false
+	}
+
+	target, ok := s.targets[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "target not found")
+		return nil, false
+	}
+
+	return target, true
+}
+
+func (s *Server) lookupTargetGroup(w http.ResponseWriter, r *http.Request) (*client.TargetGroup, bool) {
+	id := r.PathValue("id")
+	if !isValidID(id) {
+		writeError(w, http.StatusBadRequest, "invalid target group ID")
+		return nil, false
+	}
+
+	targetGroup, ok := s.targetGroups[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "target group not found")
+		return nil, false
+	}
+
+	return targetGroup, true
+}
+
+// rolesByID returns the roles with the given IDs, sorted by name.
+func (s *Server) rolesByID(ids map[string]bool) []client.Role {
+	roles := []client.Role{}
+	for id := range ids {
+		if role, ok := s.roles[id]; ok {
+			roles = append(roles, *role)
+		}
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	return roles
+}
+
+// matchesSearch implements the case-insensitive substring match of the search parameter.
+func matchesSearch(name, search string) bool {
+	return strings.Contains(strings.ToLower(name), strings.ToLower(search))
+}
+
+// newID returns a random UUIDv4 string.
+func newID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// isValidID reports whether id is a well-formed UUID.
+func isValidID(id string) bool {
+	if len(id) != 36 {
+		return false
+	}
+	for i, c := range id {
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// readJSON decodes the request body, writing a 400 response and returning false
+// if it is not valid JSON.
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %s", err))
+		return false
+	}
+	return true
+}
+
+// writeJSON writes v as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a plain text error response like the real server does.
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprint(w, message)
+}
@@ -0,0 +1,144 @@
+package warpgatetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+)
+
+const testToken = "test-token"
+
+func newTestClient(t *testing.T, s *Server, token string) *client.Client {
+	t.Helper()
+
+	c, err := client.NewClient(&client.Config{
+		Host:  client.AdminAPIURL(s.URL),
+		Token: token,
+	})
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	return c
+}
+
+func TestServerRejectsInvalidToken(t *testing.T) {
+	s := NewServer(testToken)
+	defer s.Close()
+
+	c := newTestClient(t, s, "wrong")
+
+	if _, err := c.GetRoles(context.Background(), ""); err == nil {
+		t.Fatalf("expected error for invalid token, got nil")
+	}
+}
+
+func TestServerUserLifecycle(t *testing.T) {
+	s := NewServer(testToken)
+	defer s.Close()
+
+	ctx := context.Background()
+	c := newTestClient(t, s, testToken)
+
+	user, err := c.CreateUser(ctx, &client.UserCreateRequest{Username: "alice"})
+	if err != nil {
+		t.Fatalf("CreateUser returned error: %v", err)
+	}
+
+	if _, err := c.CreateUser(ctx, &client.UserCreateRequest{Username: "alice"}); err == nil {
+		t.Fatalf("expected error for duplicate username, got nil")
+	}
+
+	if _, err := c.AddPasswordCredential(ctx, user.ID, "secret"); err != nil {
+		t.Fatalf("AddPasswordCredential returned error: %v", err)
+	}
+
+	users, err := c.GetUsers(ctx, "ALI")
+	if err != nil {
+		t.Fatalf("GetUsers returned error: %v", err)
+	}
+	if len(users) != 1 || users[0].ID != user.ID {
+		t.Fatalf("expected search to find user %s, got %v", user.ID, users)
+	}
+
+	if err := c.DeleteUser(ctx, user.ID); err != nil {
+		t.Fatalf("DeleteUser returned error: %v", err)
+	}
+
+	got, err := c.GetUser(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetUser returned error: %v", err)
+	}
+	if got != nil {
+		t.Fatalf("expected deleted user to be nil, got %v", got)
+	}
+
+	creds, err := c.GetPasswordCredentials(ctx, user.ID)
+	if err == nil && len(creds) != 0 {
+		t.Fatalf("expected credentials to be deleted with the user, got %v", creds)
+	}
+}
+
+func TestServerTargetAllowRoles(t *testing.T) {
+	s := NewServer(testToken)
+	defer s.Close()
+
+	ctx := context.Background()
+	c := newTestClient(t, s, testToken)
+
+	role, err := c.CreateRole(ctx, &client.RoleCreateRequest{Name: "admins"})
+	if err != nil {
+		t.Fatalf("CreateRole returned error: %v", err)
+	}
+
+	target, err := c.CreateTarget(ctx, &client.TargetDataRequest{
+		Name: "web",
+		Options: client.TargetHTTPOptions{
+			Kind: "Http",
+			URL:  "http://localhost:8080",
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateTarget returned error: %v", err)
+	}
+
+	if err := c.AddTargetRole(ctx, target.ID, role.ID); err != nil {
+		t.Fatalf("AddTargetRole returned error: %v", err)
+	}
+
+	if err := c.AddTargetRole(ctx, target.ID, role.ID); err == nil {
+		t.Fatalf("expected error for duplicate role assignment, got nil")
+	}
+
+	target, err = c.GetTarget(ctx, target.ID)
+	if err != nil {
+		t.Fatalf("GetTarget returned error: %v", err)
+	}
+	if len(target.AllowRoles) != 1 || target.AllowRoles[0] != "admins" {
+		t.Fatalf("expected allow_roles [admins], got %v", target.AllowRoles)
+	}
+
+	if err := c.DeleteRole(ctx, role.ID); err != nil {
+		t.Fatalf("DeleteRole returned error: %v", err)
+	}
+
+	roles, err := c.GetTargetRoles(ctx, target.ID)
+	if err != nil {
+		t.Fatalf("GetTargetRoles returned error: %v", err)
+	}
+	if len(roles) != 0 {
+		t.Fatalf("expected role assignments to be deleted with the role, got %v", roles)
+	}
+}
+
+func TestServerRejectsMalformedID(t *testing.T) {
+	s := NewServer(testToken)
+	defer s.Close()
+
+	c := newTestClient(t, s, testToken)
+
+	if _, err := c.GetRole(context.Background(), "not-a-uuid"); err == nil {
+		t.Fatalf("expected error for malformed ID, got nil")
+	}
+}
@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"log"
+	"os"
 	"runtime/debug"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6/tf6server"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/client"
+	"github.com/warp-tech/terraform-provider-warpgate/internal/export"
 	"github.com/warp-tech/terraform-provider-warpgate/internal/provider"
 )
 
@@ -26,6 +31,14 @@ var (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var debugMode bool
 
 	flag.BoolVar(&debugMode, "debug", false, "set to true to run the provider with support for debuggers like delve")
@@ -35,11 +48,65 @@ func main() {
 		log.Printf("Starting %s@%s (%s)", buildInfo.Main.Path, version, buildInfo.GoVersion)
 	}
 
-	opts := &plugin.ServeOpts{
-		Debug:        debugMode,
-		ProviderAddr: "registry.terraform.io/warp-tech/warpgate",
-		ProviderFunc: provider.New(version),
+	ctx := context.Background()
+
+	providerServer, err := provider.NewProtoV6ProviderServer(ctx, version)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var serveOpts []tf6server.ServeOpt
+	if debugMode {
+		serveOpts = append(serveOpts, tf6server.WithManagedDebug())
+	}
+
+	err = tf6server.Serve("registry.terraform.io/warp-tech/warpgate", providerServer, serveOpts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// runExport implements the "export" subcommand, which writes Terraform configuration
+// and import blocks for all objects of an existing Warpgate instance.
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s export [options]\n\n", os.Args[0])
+		fmt.Fprintf(flags.Output(), "Generates Terraform configuration with import blocks for an existing Warpgate instance.\n\n")
+		flags.PrintDefaults()
+	}
+
+	host := flags.String("host", os.Getenv("WARPGATE_HOST"), "the Warpgate host URL (defaults to $WARPGATE_HOST)")
+	token := flags.String("token", os.Getenv("WARPGATE_TOKEN"), "the Warpgate API token (defaults to $WARPGATE_TOKEN)")
+	insecureSkipVerify := flags.Bool("insecure-skip-verify", os.Getenv("WARPGATE_INSECURE_SKIP_VERIFY") == "true", "skip TLS certificate verification")
+	output := flags.String("output", "", "file to write the configuration to (defaults to stdout)")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	c, err := client.NewClient(&client.Config{
+		Host:               client.AdminAPIURL(*host),
+		Token:              *token,
+		InsecureSkipVerify: *insecureSkipVerify,
+	})
+	if err != nil {
+		return fmt.Errorf("error creating client: %w", err)
+	}
+
+	result, err := export.Generate(context.Background(), c)
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(result.Config)
+		return err
 	}
 
-	plugin.Serve(opts)
+	return os.WriteFile(*output, result.Config, 0o600)
 }